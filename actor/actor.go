@@ -1,29 +1,42 @@
 package actor
 
 import (
-	"log"
 	"math"
+	"slices"
 
 	"github.com/google/uuid"
 
+	"github.com/logging"
 	"github.com/utils"
 
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
+var logger = logging.For(logging.Actor)
+
 type Actor struct {
 	Id               string
 	Name             string
+	Archetype        string // The kind of NPC this actor was spawned as, e.g. "Ghoul"
 	Position         [2]float64
 	initialPosition  [2]float64
 	targetPosition   [2]float64
-	Image            *ebiten.Image
+	Image            *ebiten.Image // Static image, also used for the bounding rect of animated actors
+	Animator         *Animator     // Sprite animations, nil for actors drawn with the static image
+	Action           string        // Action clip being played, e.g. ClipAttack, empty while moving or idle
 	Speed            float64
 	MoveDirectionX   float64
 	MoveDirectionY   float64
 	moveRange        float64
+	Path             [][2]float64 // Waypoints the actor walks through, see FollowPath
 	Draw             bool
-	CollisionEnabled bool // Indicates if the actor can collide with other actors
+	CollisionEnabled bool     // Indicates if the actor can collide with other actors
+	flashLeft        float64  // Seconds left of the hit flash, see Flash
+	dead             bool     // Die was called, the actor is gone once it's hidden
+	leaving          bool     // Leave was called, the actor is gone once it has faded out
+	fadeLeft         float64  // Seconds left of the fade-out, see Leave
+	pooled           bool     // Created by the entity manager, which reuses it once it's removed
+	tags             []string // Groups the actor belongs to, see Tag
 }
 
 type BoundingRect struct {
@@ -34,7 +47,14 @@ type BoundingRect struct {
 }
 
 func NewActor(position [2]float64, image *ebiten.Image, speed float64, name string, collision bool) *Actor {
-	return &Actor{
+	actor := &Actor{}
+	actor.init(position, image, speed, name, collision)
+	return actor
+}
+
+// init sets up the actor as a new one, with a new Id. It resets everything, so a pooled actor can be reused.
+func (actor *Actor) init(position [2]float64, image *ebiten.Image, speed float64, name string, collision bool) {
+	*actor = Actor{
 		Id:               uuid.New().String(),
 		Name:             name,
 		Position:         position,
@@ -50,6 +70,20 @@ func NewActor(position [2]float64, image *ebiten.Image, speed float64, name stri
 	}
 }
 
+// Tag adds the actor to groups, e.g. TagNPC, so the entity manager can find it with WithTag.
+func (actor *Actor) Tag(tags ...string) {
+	for _, tag := range tags {
+		if !actor.HasTag(tag) {
+			actor.tags = append(actor.tags, tag)
+		}
+	}
+}
+
+// HasTag reports whether the actor belongs to the group.
+func (actor *Actor) HasTag(tag string) bool {
+	return slices.Contains(actor.tags, tag)
+}
+
 func (actor *Actor) ResetMoveDirection() {
 	actor.MoveDirectionX = 0
 	actor.MoveDirectionY = 0
@@ -72,15 +106,28 @@ func (actor *Actor) MoveIn(direction [2]float64) {
 	// Pythagorean theorem for the hypotenuse od a right triangle
 	distance := math.Sqrt(math.Pow(dx, 2) + math.Pow(dy, 2))
 	if distance > 0 {
-		// normalize the direction vector to get smooth movement on the diagonals
-		dx = (dx / distance) * actor.Speed
-		dy = (dy / distance) * actor.Speed
+		// normalize the direction vector to get smooth movement on the diagonals,
+		// shorter vectors (a half-pushed analog stick) move the actor slower
+		scale := max(distance, 1)
+		dx = (dx / scale) * actor.Speed
+		dy = (dy / scale) * actor.Speed
 
 		actor.Position[0] += dx
 		actor.Position[1] += dy
 	}
 }
 
+// Contains reports whether the point is inside the rectangle.
+func (rect *BoundingRect) Contains(point [2]float64) bool {
+	return point[0] >= rect.PositionX && point[0] < rect.PositionX+rect.Width &&
+		point[1] >= rect.PositionY && point[1] < rect.PositionY+rect.Height
+}
+
+// Center returns the center of the rectangle.
+func (rect *BoundingRect) Center() [2]float64 {
+	return [2]float64{rect.PositionX + rect.Width/2, rect.PositionY + rect.Height/2}
+}
+
 // GetBoundingRect calculates the bounding rectangle of the actor.
 // The bounding rectangle is used for collision detection.
 // It takes into account the actor's position and the dimensions of the image.
@@ -103,20 +150,25 @@ func (actor *Actor) CollidesWith(npc *Actor) bool {
 	playerRect := actor.GetBoundingRect()
 	npcRect := npc.GetBoundingRect()
 
-	return rectCollition(playerRect, npcRect)
+	if !rectCollition(playerRect, npcRect) {
+		return false
+	}
+	logger.Debug("collision", "actor", actor.Name, "id", actor.Id, "other", npc.Name, "otherId", npc.Id)
+	return true
 }
 
 // TODO: maybe move to phisics package?
 func rectCollition(rect1, rect2 *BoundingRect) bool {
-	if rect1.PositionX < rect2.PositionX+rect2.Width &&
-		rect1.PositionX+rect1.Width > rect2.PositionX &&
-		rect1.PositionY < rect2.PositionY+rect2.Height &&
-		rect1.PositionY+rect1.Height > rect2.PositionY {
-		log.Print("Collision!!!!!--------")
-		return true
-	}
+	return rect1.Intersects(rect2)
+}
 
-	return false
+// Intersects checks if two bounding rectangles overlap.
+// Unlike CollidesWith, it works on plain rectangles, so it can be used for map obstacles and zones.
+func (rect *BoundingRect) Intersects(other *BoundingRect) bool {
+	return rect.PositionX < other.PositionX+other.Width &&
+		rect.PositionX+rect.Width > other.PositionX &&
+		rect.PositionY < other.PositionY+other.Height &&
+		rect.PositionY+rect.Height > other.PositionY
 }
 
 func (actor *Actor) CollidesWithAbility(ability *Actor) bool {
@@ -154,6 +206,8 @@ func (actor *Actor) MoveTo(targetPosition [2]float64) {
 	dy := targetPosition[1] - y
 
 	teta := math.Atan2(dy, dx)
+	actor.MoveDirectionX = math.Cos(teta)
+	actor.MoveDirectionY = math.Sin(teta)
 
 	nextPositionX := actor.Position[0] + math.Cos(teta)*actor.Speed
 	nextPositionY := actor.Position[1] + math.Sin(teta)*actor.Speed
@@ -162,18 +216,41 @@ func (actor *Actor) MoveTo(targetPosition [2]float64) {
 	actor.Position[1] = nextPositionY
 
 	// if x or y oversteps the target position, set it to the target position (clamping)
-	if dx > 0 && nextPositionX > actor.targetPosition[0] {
-		actor.Position[0] = actor.targetPosition[0]
+	if dx > 0 && nextPositionX > targetPosition[0] {
+		actor.Position[0] = targetPosition[0]
 	}
-	if dx < 0 && nextPositionX < actor.targetPosition[0] {
-		actor.Position[0] = actor.targetPosition[0]
+	if dx < 0 && nextPositionX < targetPosition[0] {
+		actor.Position[0] = targetPosition[0]
 	}
-	if dy > 0 && nextPositionY > actor.targetPosition[1] {
-		actor.Position[1] = actor.targetPosition[1]
+	if dy > 0 && nextPositionY > targetPosition[1] {
+		actor.Position[1] = targetPosition[1]
+	}
+	if dy < 0 && nextPositionY < targetPosition[1] {
+		actor.Position[1] = targetPosition[1]
+	}
+}
+
+// FollowPath moves the actor towards the first waypoint of its path.
+// Once a waypoint is reached it is dropped, so the actor walks the whole path over several ticks.
+// Returns false when there are no waypoints left.
+func (actor *Actor) FollowPath() bool {
+	if len(actor.Path) == 0 {
+		actor.ResetMoveDirection()
+		return false
 	}
-	if dy < 0 && nextPositionY < actor.targetPosition[1] {
-		actor.Position[1] = actor.targetPosition[1]
+
+	waypoint := actor.Path[0]
+	dx := waypoint[0] - actor.Position[0]
+	dy := waypoint[1] - actor.Position[1]
+
+	if math.Hypot(dx, dy) <= actor.Speed {
+		actor.Position = waypoint
+		actor.Path = actor.Path[1:]
+		return len(actor.Path) > 0
 	}
+
+	actor.MoveTo(waypoint)
+	return true
 }
 
 // Initiates a patrol movement for the actor.
@@ -189,6 +266,11 @@ func (actor *Actor) Patrol(moveRange float64) {
 	actor.MoveTo(actor.targetPosition)
 }
 
+// PatrolTarget returns the point the actor patrols to, see Patrol.
+func (actor *Actor) PatrolTarget() [2]float64 {
+	return actor.targetPosition
+}
+
 // SetLimitBounds sets the limits for the actor's movement.
 // It ensures that the actor does not move outside the specified bounds (limiX, limitY).
 func (actor *Actor) SetLimitBounds(limiX, limitY float64) {
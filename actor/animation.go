@@ -0,0 +1,236 @@
+package actor
+
+import (
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Names of the animation clips an actor can play.
+// The walk clips are picked from the actor's movement direction, the others are actions.
+const (
+	ClipIdle      = "idle"
+	ClipWalkLeft  = "walk-left"
+	ClipWalkRight = "walk-right"
+	ClipWalkUp    = "walk-up"
+	ClipWalkDown  = "walk-down"
+	ClipAttack    = "attack"
+	ClipDeath     = "death"
+)
+
+// Animation is a clip of frames shown one after another.
+type Animation struct {
+	Frames        []*ebiten.Image
+	FrameDuration float64 // Seconds each frame is shown for
+	Loop          bool    // Start over after the last frame, or stay on it
+}
+
+// Duration returns the number of seconds it takes to play the clip once.
+func (animation *Animation) Duration() float64 {
+	return float64(len(animation.Frames)) * animation.FrameDuration
+}
+
+// Animator plays the clips of an actor and keeps track of the current frame.
+type Animator struct {
+	Clips    map[string]*Animation
+	Current  string  // Name of the clip being played
+	elapsed  float64 // Seconds since the current clip started
+	finished bool    // The current clip doesn't loop and has shown its last frame
+}
+
+// NewAnimator creates an animator that starts with the idle clip.
+func NewAnimator(clips map[string]*Animation) *Animator {
+	return &Animator{Clips: clips, Current: ClipIdle}
+}
+
+// Play switches to the clip with the given name. Playing the current clip again doesn't restart it.
+// If the actor doesn't have the clip, it falls back to the idle clip.
+func (animator *Animator) Play(clip string) {
+	if _, ok := animator.Clips[clip]; !ok {
+		clip = ClipIdle
+	}
+	if clip == animator.Current {
+		return
+	}
+	animator.Current = clip
+	animator.elapsed = 0
+	animator.finished = false
+}
+
+// Update advances the current clip by the given number of seconds.
+func (animator *Animator) Update(delta float64) {
+	animation, ok := animator.Clips[animator.Current]
+	if !ok || len(animation.Frames) == 0 {
+		return
+	}
+
+	animator.elapsed += delta
+	if !animation.Loop && animator.elapsed >= animation.Duration() {
+		animator.elapsed = animation.Duration()
+		animator.finished = true
+	}
+}
+
+// Finished reports whether a clip that doesn't loop has been played to the end.
+func (animator *Animator) Finished() bool {
+	return animator.finished
+}
+
+// Frame returns the frame of the current clip to draw, or nil if the actor doesn't have the clip.
+func (animator *Animator) Frame() *ebiten.Image {
+	animation, ok := animator.Clips[animator.Current]
+	if !ok || len(animation.Frames) == 0 {
+		return nil
+	}
+	if animation.FrameDuration <= 0 {
+		return animation.Frames[0]
+	}
+
+	index := int(animator.elapsed / animation.FrameDuration)
+	if animation.Loop {
+		index %= len(animation.Frames)
+	}
+	return animation.Frames[min(index, len(animation.Frames)-1)]
+}
+
+// SliceSpriteSheet cuts a row of frames out of a sprite sheet.
+// All frames have the same size, and the rows and columns are counted from the top-left corner of the sheet.
+func SliceSpriteSheet(sheet *ebiten.Image, frameWidth, frameHeight, row, frameCount int) []*ebiten.Image {
+	frames := make([]*ebiten.Image, 0, frameCount)
+	for column := range frameCount {
+		frameRect := image.Rect(column*frameWidth, row*frameHeight, (column+1)*frameWidth, (row+1)*frameHeight)
+		frames = append(frames, sheet.SubImage(frameRect).(*ebiten.Image))
+	}
+	return frames
+}
+
+// PlayAction plays an action clip (attack, death, etc.) over the movement clips until it's finished.
+func (actor *Actor) PlayAction(clip string) {
+	actor.Action = clip
+	if actor.Animator != nil {
+		actor.Animator.Play(clip)
+	}
+}
+
+// Die plays the actor's death clip and takes it out of the collisions.
+// The actor is hidden once the clip ends, or right away if it doesn't have a death clip.
+func (actor *Actor) Die() {
+	actor.dead = true
+	actor.CollisionEnabled = false
+	if actor.Animator == nil || actor.Animator.Clips[ClipDeath] == nil {
+		actor.Draw = false
+		return
+	}
+	actor.PlayAction(ClipDeath)
+}
+
+// leaveFadeDuration is the number of seconds an actor takes to fade out when it leaves.
+const leaveFadeDuration = 0.6
+
+// Leave makes the actor walk out of the game alive, e.g. a spared citizen. It stops, fades out
+// and is hidden once the fade is over. Unlike Die, it doesn't play the death clip.
+func (actor *Actor) Leave() {
+	actor.leaving = true
+	actor.fadeLeft = leaveFadeDuration
+	actor.CollisionEnabled = false
+	actor.Path = nil
+	actor.ResetMoveDirection()
+}
+
+// Opacity returns how opaque the actor is drawn, from 1 down to 0 at the end of its fade-out.
+func (actor *Actor) Opacity() float64 {
+	if !actor.leaving {
+		return 1
+	}
+	return actor.fadeLeft / leaveFadeDuration
+}
+
+// hitFlashDuration is the number of seconds an actor flashes white when it's hit.
+const hitFlashDuration = 0.15
+
+// Flash makes the actor flash white, e.g. when it takes damage. The flash fades with the animation updates.
+// A hit during the flash doesn't restart it, so the damage over time makes the actor pulse.
+func (actor *Actor) Flash() {
+	if actor.flashLeft == 0 {
+		actor.flashLeft = hitFlashDuration
+	}
+}
+
+// FlashAmount returns how white the actor is drawn, from 1 right after the hit to 0 once the flash is over.
+func (actor *Actor) FlashAmount() float64 {
+	return actor.flashLeft / hitFlashDuration
+}
+
+// Gone reports whether the actor died or left and is hidden, i.e. its death clip or its fade-out is over.
+// A hidden actor that didn't die, like a burrowed boss, isn't gone.
+func (actor *Actor) Gone() bool {
+	return (actor.dead || actor.leaving) && !actor.Draw
+}
+
+// Dying reports whether the actor is playing its death clip.
+func (actor *Actor) Dying() bool {
+	return actor.Action == ClipDeath
+}
+
+// Present reports whether the actor is in play - drawn, and neither dying nor leaving.
+// The player only targets and hits the NPCs that are present.
+func (actor *Actor) Present() bool {
+	return actor.Draw && !actor.Dying() && !actor.leaving
+}
+
+// UpdateAnimation advances the actor's animation by the given number of seconds.
+// While an action is playing, it is shown until it ends. Otherwise the clip follows the movement
+// direction - the stronger of the two axes wins on the diagonals - or the actor idles.
+func (actor *Actor) UpdateAnimation(delta float64) {
+	actor.flashLeft = max(actor.flashLeft-delta, 0)
+	if actor.leaving {
+		actor.fadeLeft = max(actor.fadeLeft-delta, 0)
+		if actor.fadeLeft == 0 {
+			actor.Draw = false
+		}
+	}
+	if actor.Animator == nil {
+		return
+	}
+
+	if actor.Action != "" && actor.Animator.Finished() {
+		if actor.Action == ClipDeath {
+			actor.Draw = false
+			return
+		}
+		actor.Action = ""
+	}
+
+	if actor.Action == "" {
+		actor.Animator.Play(actor.movementClip())
+	}
+	actor.Animator.Update(delta)
+}
+
+func (actor *Actor) movementClip() string {
+	dx, dy := actor.MoveDirectionX, actor.MoveDirectionY
+	switch {
+	case dx == 0 && dy == 0:
+		return ClipIdle
+	case math.Abs(dx) >= math.Abs(dy) && dx < 0:
+		return ClipWalkLeft
+	case math.Abs(dx) >= math.Abs(dy):
+		return ClipWalkRight
+	case dy < 0:
+		return ClipWalkUp
+	default:
+		return ClipWalkDown
+	}
+}
+
+// CurrentFrame returns the image to draw for the actor - the current animation frame,
+// or the actor's static image if it isn't animated.
+func (actor *Actor) CurrentFrame() *ebiten.Image {
+	if actor.Animator != nil {
+		if frame := actor.Animator.Frame(); frame != nil {
+			return frame
+		}
+	}
+	return actor.Image
+}
@@ -5,6 +5,7 @@ go 1.24.2
 require (
 	github.com/google/uuid v1.6.0
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
+	github.com/logging v0.0.0-00010101000000-000000000000
 	github.com/utils v0.0.0-00010101000000-000000000000
 )
 
@@ -18,3 +19,5 @@ require (
 )
 
 replace github.com/utils => ../utils
+
+replace github.com/logging => ../logging
@@ -0,0 +1,144 @@
+package actor
+
+import (
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Tags of the actors the game looks for.
+const (
+	TagPlayer = "player"
+	TagNPC    = "npc"  // NPCs the player can target, purge or spare, bosses included
+	TagBoss   = "boss" // Actors driven by the boss framework
+	TagAlly   = "ally" // Friendly characters of the mode, e.g. the escorted Jaina
+)
+
+// Manager owns the actors of a game. It finds them by Id, tag or archetype.
+// Adding and removing actors is deferred to Flush, at the end of the tick, so the actors don't change
+// while a tick goes through them. The actors created by New are pooled once removed and reused by New.
+// The ones created elsewhere and added with Add, like the player or a boss, belong to their creator and aren't reused.
+type Manager struct {
+	OnRemove func(actor *Actor) // Called by Flush for each live actor it removes, to drop what points to it
+
+	actors   []*Actor // Live actors, in the order they were added
+	byId     map[string]*Actor
+	added    []*Actor
+	removed  []*Actor
+	released []*Actor // Removed in the last flush, pooled in the next one
+	pool     []*Actor
+}
+
+// NewManager creates an empty entity manager.
+func NewManager() *Manager {
+	return &Manager{byId: map[string]*Actor{}}
+}
+
+// New creates an actor with the tags, reusing a pooled one if there is one, and adds it at the next flush.
+func (manager *Manager) New(position [2]float64, image *ebiten.Image, speed float64, name string, collision bool, tags ...string) *Actor {
+	var actor *Actor
+	if last := len(manager.pool) - 1; last >= 0 {
+		actor = manager.pool[last]
+		manager.pool = manager.pool[:last]
+	} else {
+		actor = &Actor{}
+	}
+	actor.init(position, image, speed, name, collision)
+	actor.pooled = true
+	actor.Tag(tags...)
+	manager.Add(actor)
+	return actor
+}
+
+// Add adds actors created outside of the manager at the next flush, e.g. the player.
+func (manager *Manager) Add(actors ...*Actor) {
+	manager.added = append(manager.added, actors...)
+}
+
+// Remove removes the actor at the next flush. An actor created by New is pooled, so it must not be used once it's removed.
+func (manager *Manager) Remove(actor *Actor) {
+	manager.removed = append(manager.removed, actor)
+}
+
+// Flush applies the adds and the removes of the tick. An actor added and removed in the same tick never goes live.
+// A removed actor is only reused after one more flush, so whatever still points to it
+// and isn't told by OnRemove sees it hidden for a tick and lets it go.
+func (manager *Manager) Flush() {
+	manager.pool = append(manager.pool, manager.released...)
+	manager.released = manager.released[:0]
+
+	for _, actor := range manager.added {
+		if slices.Contains(manager.removed, actor) {
+			manager.release(actor)
+			continue
+		}
+		if _, ok := manager.byId[actor.Id]; ok {
+			continue
+		}
+		manager.byId[actor.Id] = actor
+		manager.actors = append(manager.actors, actor)
+	}
+	manager.added = manager.added[:0]
+
+	if len(manager.removed) > 0 {
+		for _, actor := range manager.removed {
+			if manager.byId[actor.Id] != actor {
+				continue
+			}
+			delete(manager.byId, actor.Id)
+			manager.release(actor)
+			if manager.OnRemove != nil {
+				manager.OnRemove(actor)
+			}
+		}
+		live := manager.actors[:0]
+		for _, actor := range manager.actors {
+			if manager.byId[actor.Id] == actor {
+				live = append(live, actor)
+			}
+		}
+		clear(manager.actors[len(live):])
+		manager.actors = live
+		manager.removed = manager.removed[:0]
+	}
+}
+
+// release pools the actor at the next flush, if the manager created it.
+func (manager *Manager) release(actor *Actor) {
+	if actor.pooled && !slices.Contains(manager.released, actor) {
+		manager.released = append(manager.released, actor)
+	}
+}
+
+// Get returns the live actor with the Id, and false if there is none.
+func (manager *Manager) Get(id string) (*Actor, bool) {
+	actor, ok := manager.byId[id]
+	return actor, ok
+}
+
+// All returns the live actors, in the order they were added. The slice is only valid until the next flush.
+func (manager *Manager) All() []*Actor {
+	return manager.actors
+}
+
+// WithTag returns the live actors with the tag.
+func (manager *Manager) WithTag(tag string) []*Actor {
+	var actors []*Actor
+	for _, actor := range manager.actors {
+		if actor.HasTag(tag) {
+			actors = append(actors, actor)
+		}
+	}
+	return actors
+}
+
+// WithArchetype returns the live NPCs spawned as the archetype, e.g. all the Ghouls.
+func (manager *Manager) WithArchetype(archetype string) []*Actor {
+	var actors []*Actor
+	for _, actor := range manager.actors {
+		if actor.Archetype == archetype {
+			actors = append(actors, actor)
+		}
+	}
+	return actors
+}
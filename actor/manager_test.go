@@ -0,0 +1,106 @@
+package actor
+
+import "testing"
+
+// newNPC creates an NPC in the manager, without an image, which the manager doesn't need.
+func newNPC(manager *Manager) *Actor {
+	return manager.New([2]float64{0, 0}, nil, 1, "Ghoul", true, TagNPC)
+}
+
+func TestManagerAddAfterFlush(t *testing.T) {
+	manager := NewManager()
+	npc := newNPC(manager)
+
+	if _, ok := manager.Get(npc.Id); ok {
+		t.Error("the NPC is live before the flush")
+	}
+	if npcs := manager.WithTag(TagNPC); len(npcs) != 0 {
+		t.Errorf("got %d NPCs before the flush, want none", len(npcs))
+	}
+
+	manager.Flush()
+
+	if got, ok := manager.Get(npc.Id); !ok || got != npc {
+		t.Error("the NPC isn't live after the flush")
+	}
+	if npcs := manager.WithTag(TagNPC); len(npcs) != 1 || npcs[0] != npc {
+		t.Errorf("got the NPCs %v, want only the new one", npcs)
+	}
+}
+
+func TestManagerRemove(t *testing.T) {
+	manager := NewManager()
+	npc := newNPC(manager)
+	manager.Flush()
+	id := npc.Id
+
+	var removed []*Actor
+	manager.OnRemove = func(actor *Actor) { removed = append(removed, actor) }
+	manager.Remove(npc)
+	manager.Flush()
+
+	if _, ok := manager.Get(id); ok {
+		t.Error("the NPC is still live after its removal")
+	}
+	if len(manager.All()) != 0 {
+		t.Errorf("got %d live actors, want none", len(manager.All()))
+	}
+	if len(removed) != 1 || removed[0] != npc {
+		t.Errorf("OnRemove got %v, want only the removed NPC", removed)
+	}
+}
+
+func TestManagerReusesAfterSecondFlush(t *testing.T) {
+	manager := NewManager()
+	npc := newNPC(manager)
+	manager.Flush()
+	manager.Remove(npc)
+	manager.Flush()
+
+	if next := newNPC(manager); next == npc {
+		t.Fatal("the NPC was reused right after its removal")
+	}
+	manager.Flush()
+
+	next := newNPC(manager)
+	if next != npc {
+		t.Fatal("the NPC wasn't reused after the second flush")
+	}
+	if !next.Draw || next.Name != "Ghoul" {
+		t.Error("the reused NPC wasn't reset")
+	}
+}
+
+func TestManagerAddAndRemoveInOneTick(t *testing.T) {
+	manager := NewManager()
+	removed := 0
+	manager.OnRemove = func(*Actor) { removed++ }
+
+	npc := newNPC(manager)
+	manager.Remove(npc)
+	manager.Flush()
+
+	if _, ok := manager.Get(npc.Id); ok {
+		t.Error("the NPC removed in the tick it was added went live")
+	}
+	if len(manager.All()) != 0 {
+		t.Errorf("got %d live actors, want none", len(manager.All()))
+	}
+	if removed != 0 {
+		t.Errorf("OnRemove was called %d times for an NPC that never went live", removed)
+	}
+}
+
+func TestManagerDoesNotReuseAddedActors(t *testing.T) {
+	manager := NewManager()
+	player := NewActor([2]float64{0, 0}, nil, 1, "Player", true)
+	manager.Add(player)
+	manager.Flush()
+	manager.Remove(player)
+	manager.Flush()
+	manager.Flush()
+
+	if npc := newNPC(manager); npc == player {
+		t.Error("an actor added from outside the manager was reused")
+	}
+}
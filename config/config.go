@@ -0,0 +1,295 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/input"
+	"github.com/logging"
+)
+
+// Path is the default settings file.
+const Path = "config/settings.json"
+
+// SavePath is the default save file, where the talent choices are kept from game to game.
+const SavePath = "config/save.json"
+
+// Config holds the game settings. They are layered - the defaults, then the settings file,
+// then the environment, then the command-line flags, each one overriding the previous.
+type Config struct {
+	Debug         bool    `json:"debug"`
+	Scale         float64 `json:"scale"`         // Window size as a multiple of the screen size
+	Fullscreen    bool    `json:"fullscreen"`    // Start in fullscreen
+	TPS           int     `json:"tps"`           // Game ticks per second
+	Mode          int     `json:"mode"`          // Game mode selected on the home screen
+	Seed          uint64  `json:"seed"`          // Seed of the random numbers, 0 for a different game each time
+	AssetPath     string  `json:"assetPath"`     // Directory of the textures
+	Volume        float64 `json:"volume"`        // Master volume, from 0 to 1
+	MusicVolume   float64 `json:"musicVolume"`   // Volume of the music, from 0 to 1
+	EffectsVolume float64 `json:"effectsVolume"` // Volume of the sound effects, from 0 to 1
+	ControlsPath  string  `json:"controlsPath"`  // File the key bindings are loaded from and saved to
+	SavePath      string  `json:"savePath"`      // File the talent choices are loaded from and saved to
+	Log           string  `json:"log"`           // Log levels of the subsystems, see logging.Configure
+	Script        string  `json:"script"`        // Console commands run at startup, one per line, optional
+	Lighting      bool    `json:"lighting"`      // Light the night maps and draw their fog-of-war, off for the low-end machines
+	XPBase        int     `json:"xpBase"`        // Experience needed for the first level-up
+	XPGrowth      float64 `json:"xpGrowth"`      // Factor of the experience needed for each following level
+	File          string  `json:"-"`             // Settings file the settings were loaded from, see Save
+}
+
+// Defaults returns the settings used when nothing overrides them.
+func Defaults() Config {
+	return Config{
+		Scale:         2,
+		TPS:           60,
+		Mode:          2, // Frostmourne Hungers
+		AssetPath:     "./assets",
+		Volume:        0.8,
+		MusicVolume:   0.6,
+		EffectsVolume: 1,
+		ControlsPath:  input.BindingsPath,
+		SavePath:      SavePath,
+		Log:           logging.DefaultSpec,
+		Lighting:      true,
+		XPBase:        40,
+		XPGrowth:      1.5,
+	}
+}
+
+// Load builds the settings from all the layers and validates them.
+// The settings file is the one given by the -config flag or SCOURGE_CONFIG, or Path,
+// and it's fine if it doesn't exist. args are the command-line arguments without the program name.
+func Load(args []string) (Config, error) {
+	settings := Defaults()
+
+	setFlags, err := parseFlags(args)
+	if err != nil {
+		return settings, err
+	}
+
+	path := Path
+	if envPath, ok := os.LookupEnv("SCOURGE_CONFIG"); ok {
+		path = envPath
+	}
+	if flagPath, ok := setFlags["config"]; ok {
+		path = flagPath.Value.String()
+	}
+
+	settings.File = path
+	if err := settings.applyFile(path); err != nil {
+		return settings, err
+	}
+	if err := settings.applyEnv(); err != nil {
+		return settings, err
+	}
+	if err := settings.applyFlags(setFlags); err != nil {
+		return settings, err
+	}
+	return settings, settings.Validate()
+}
+
+// applyFile overrides the settings present in the JSON settings file.
+func (settings *Config) applyFile(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("config: reading %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return fmt.Errorf("config: %s is not valid JSON: %w", path, err)
+	}
+	return nil
+}
+
+// Save sets the values in the settings file, keeping the rest of the file. The keys are the JSON names of the settings,
+// so the file only gets the settings changed in the game and not the ones coming from the environment or the flags.
+func Save(path string, values map[string]any) error {
+	file := map[string]any{}
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("config: reading %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &file); err != nil {
+			return fmt.Errorf("config: %s is not valid JSON: %w", path, err)
+		}
+	}
+	for key, value := range values {
+		file[key] = value
+	}
+
+	data, err = json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// applyEnv overrides the settings with the SCOURGE_* environment variables.
+// DEBUG=TRUE still turns on the debug mode, as it did before the settings file.
+func (settings *Config) applyEnv() error {
+	if debug, ok := os.LookupEnv("DEBUG"); ok {
+		settings.Debug = strings.EqualFold(debug, "TRUE")
+	}
+
+	var errs []error
+	lookup := func(name string, apply func(value string) error) {
+		if value, ok := os.LookupEnv(name); ok {
+			if err := apply(value); err != nil {
+				errs = append(errs, fmt.Errorf("config: %s=%q: %w", name, value, err))
+			}
+		}
+	}
+	lookup("SCOURGE_DEBUG", parseInto(&settings.Debug, strconv.ParseBool))
+	lookup("SCOURGE_SCALE", parseInto(&settings.Scale, parseFloat))
+	lookup("SCOURGE_FULLSCREEN", parseInto(&settings.Fullscreen, strconv.ParseBool))
+	lookup("SCOURGE_TPS", parseInto(&settings.TPS, strconv.Atoi))
+	lookup("SCOURGE_MODE", parseInto(&settings.Mode, strconv.Atoi))
+	lookup("SCOURGE_SEED", parseInto(&settings.Seed, parseUint))
+	lookup("SCOURGE_ASSETS", func(value string) error { settings.AssetPath = value; return nil })
+	lookup("SCOURGE_VOLUME", parseInto(&settings.Volume, parseFloat))
+	lookup("SCOURGE_MUSIC_VOLUME", parseInto(&settings.MusicVolume, parseFloat))
+	lookup("SCOURGE_EFFECTS_VOLUME", parseInto(&settings.EffectsVolume, parseFloat))
+	lookup("SCOURGE_CONTROLS", func(value string) error { settings.ControlsPath = value; return nil })
+	lookup("SCOURGE_SAVE", func(value string) error { settings.SavePath = value; return nil })
+	lookup("SCOURGE_LOG", func(value string) error { settings.Log = value; return nil })
+	lookup("SCOURGE_SCRIPT", func(value string) error { settings.Script = value; return nil })
+	lookup("SCOURGE_LIGHTING", parseInto(&settings.Lighting, strconv.ParseBool))
+	lookup("SCOURGE_XP_BASE", parseInto(&settings.XPBase, strconv.Atoi))
+	lookup("SCOURGE_XP_GROWTH", parseInto(&settings.XPGrowth, parseFloat))
+	return errors.Join(errs...)
+}
+
+func parseInto[T any](target *T, parse func(string) (T, error)) func(string) error {
+	return func(value string) error {
+		parsed, err := parse(value)
+		if err != nil {
+			return err
+		}
+		*target = parsed
+		return nil
+	}
+}
+
+func parseFloat(value string) (float64, error) { return strconv.ParseFloat(value, 64) }
+func parseUint(value string) (uint64, error)   { return strconv.ParseUint(value, 10, 64) }
+
+// parseFlags parses the command line and returns the flags that were set, by name.
+// The flags are applied last, so their values are only read after the other layers.
+func parseFlags(args []string) (map[string]*flag.Flag, error) {
+	defaults := Defaults()
+	flags := flag.NewFlagSet("scourgehunt", flag.ContinueOnError)
+	flags.String("config", Path, "settings file")
+	flags.Bool("debug", defaults.Debug, "draw the debug information")
+	flags.Float64("scale", defaults.Scale, "window size as a multiple of the screen size")
+	flags.Bool("fullscreen", defaults.Fullscreen, "start in fullscreen")
+	flags.Int("tps", defaults.TPS, "game ticks per second")
+	flags.Int("mode", defaults.Mode, "game mode selected on the home screen")
+	flags.Uint64("seed", defaults.Seed, "seed of the random numbers, 0 for a different game each time")
+	flags.String("assets", defaults.AssetPath, "directory of the textures")
+	flags.Float64("volume", defaults.Volume, "master volume, from 0 to 1")
+	flags.Float64("music-volume", defaults.MusicVolume, "volume of the music, from 0 to 1")
+	flags.Float64("effects-volume", defaults.EffectsVolume, "volume of the sound effects, from 0 to 1")
+	flags.String("controls", defaults.ControlsPath, "file the key bindings are loaded from and saved to")
+	flags.String("save", defaults.SavePath, "file the talent choices are loaded from and saved to")
+	flags.String("script", defaults.Script, "file of console commands run at startup")
+	flags.String("log", defaults.Log, "log levels, e.g. \"warn,gameplay=debug,actor=off\"")
+	flags.Bool("lighting", defaults.Lighting, "light the night maps and draw their fog-of-war")
+	flags.Int("xp-base", defaults.XPBase, "experience needed for the first level-up")
+	flags.Float64("xp-growth", defaults.XPGrowth, "factor of the experience needed for each following level")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	setFlags := map[string]*flag.Flag{}
+	flags.Visit(func(set *flag.Flag) { setFlags[set.Name] = set })
+	return setFlags, nil
+}
+
+// applyFlags overrides the settings given on the command line.
+func (settings *Config) applyFlags(setFlags map[string]*flag.Flag) error {
+	var errs []error
+	apply := func(name string, parse func(string) error) {
+		if set, ok := setFlags[name]; ok {
+			if err := parse(set.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("config: -%s=%s: %w", name, set.Value, err))
+			}
+		}
+	}
+	apply("debug", parseInto(&settings.Debug, strconv.ParseBool))
+	apply("scale", parseInto(&settings.Scale, parseFloat))
+	apply("fullscreen", parseInto(&settings.Fullscreen, strconv.ParseBool))
+	apply("tps", parseInto(&settings.TPS, strconv.Atoi))
+	apply("mode", parseInto(&settings.Mode, strconv.Atoi))
+	apply("seed", parseInto(&settings.Seed, parseUint))
+	apply("assets", func(path string) error { settings.AssetPath = path; return nil })
+	apply("volume", parseInto(&settings.Volume, parseFloat))
+	apply("music-volume", parseInto(&settings.MusicVolume, parseFloat))
+	apply("effects-volume", parseInto(&settings.EffectsVolume, parseFloat))
+	apply("controls", func(path string) error { settings.ControlsPath = path; return nil })
+	apply("save", func(path string) error { settings.SavePath = path; return nil })
+	apply("log", func(spec string) error { settings.Log = spec; return nil })
+	apply("script", func(path string) error { settings.Script = path; return nil })
+	apply("lighting", parseInto(&settings.Lighting, strconv.ParseBool))
+	apply("xp-base", parseInto(&settings.XPBase, strconv.Atoi))
+	apply("xp-growth", parseInto(&settings.XPGrowth, parseFloat))
+	return errors.Join(errs...)
+}
+
+// Validate checks that the settings can be used, and reports all the invalid ones at once.
+func (settings *Config) Validate() error {
+	var errs []error
+	if settings.Scale <= 0 || settings.Scale > 4 {
+		errs = append(errs, fmt.Errorf("config: scale must be more than 0 and at most 4, got %g", settings.Scale))
+	}
+	if settings.TPS < 10 || settings.TPS > 240 {
+		errs = append(errs, fmt.Errorf("config: tps must be between 10 and 240, got %d", settings.TPS))
+	}
+	if settings.Mode < 1 {
+		errs = append(errs, fmt.Errorf("config: mode must be the number of a game mode, starting at 1, got %d", settings.Mode))
+	}
+	volumes := []struct {
+		name  string
+		value float64
+	}{{"volume", settings.Volume}, {"music volume", settings.MusicVolume}, {"effects volume", settings.EffectsVolume}}
+	for _, volume := range volumes {
+		if volume.value < 0 || volume.value > 1 {
+			errs = append(errs, fmt.Errorf("config: %s must be between 0 and 1, got %g", volume.name, volume.value))
+		}
+	}
+	if info, err := os.Stat(settings.AssetPath); err != nil || !info.IsDir() {
+		errs = append(errs, fmt.Errorf("config: the asset path %q is not a directory - run the game from the repository root or set -assets", settings.AssetPath))
+	}
+	if settings.ControlsPath == "" {
+		errs = append(errs, errors.New("config: the controls path can't be empty"))
+	}
+	if settings.SavePath == "" {
+		errs = append(errs, errors.New("config: the save path can't be empty"))
+	}
+	if settings.Script != "" {
+		if _, err := os.Stat(settings.Script); err != nil {
+			errs = append(errs, fmt.Errorf("config: the script %q can't be read: %w", settings.Script, err))
+		}
+	}
+	if settings.XPBase < 1 {
+		errs = append(errs, fmt.Errorf("config: xp base must be at least 1, got %d", settings.XPBase))
+	}
+	if settings.XPGrowth < 1 {
+		errs = append(errs, fmt.Errorf("config: xp growth must be at least 1, got %g", settings.XPGrowth))
+	}
+	if _, _, err := logging.ParseSpec(settings.Log); err != nil {
+		errs = append(errs, fmt.Errorf("config: log: %w", err))
+	}
+	return errors.Join(errs...)
+}
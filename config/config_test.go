@@ -0,0 +1,95 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearEnv unsets the settings variables of the environment for the test, so only the ones the test sets are read.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, variable := range os.Environ() {
+		name, _, _ := strings.Cut(variable, "=")
+		if strings.HasPrefix(name, "SCOURGE_") || name == "DEBUG" {
+			t.Setenv(name, "")
+			os.Unsetenv(name)
+		}
+	}
+}
+
+// writeSettings writes the settings file of the test and returns its path.
+func writeSettings(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	clearEnv(t)
+	assets := t.TempDir()
+	path := writeSettings(t, `{"scale": 3, "tps": 30, "mode": 3, "seed": 7}`)
+	t.Setenv("SCOURGE_CONFIG", path)
+	t.Setenv("SCOURGE_MODE", "4")
+	t.Setenv("SCOURGE_SEED", "8")
+
+	settings, err := Load([]string{"-seed", "9", "-assets", assets})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := Defaults()
+	if settings.Volume != defaults.Volume {
+		t.Errorf("volume = %g, want the default %g", settings.Volume, defaults.Volume)
+	}
+	if settings.Scale != 3 || settings.TPS != 30 {
+		t.Errorf("scale = %g and tps = %d, want 3 and 30 from the file", settings.Scale, settings.TPS)
+	}
+	if settings.Mode != 4 {
+		t.Errorf("mode = %d, want 4 from the environment over the file", settings.Mode)
+	}
+	if settings.Seed != 9 {
+		t.Errorf("seed = %d, want 9 from the flags over the environment and the file", settings.Seed)
+	}
+	if settings.File != path {
+		t.Errorf("file = %q, want %q", settings.File, path)
+	}
+}
+
+func TestLoadWithoutFile(t *testing.T) {
+	clearEnv(t)
+	assets := t.TempDir()
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	settings, err := Load([]string{"-config", path, "-assets", assets})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Defaults()
+	want.AssetPath = assets
+	want.File = path
+	if settings != want {
+		t.Errorf("got %+v, want the defaults %+v", settings, want)
+	}
+}
+
+func TestLoadReportsEveryInvalidSetting(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("SCOURGE_TPS", "5")
+	path := filepath.Join(t.TempDir(), "missing.json")
+
+	_, err := Load([]string{"-config", path, "-scale", "9", "-assets", t.TempDir()})
+	if err == nil {
+		t.Fatal("the invalid settings were accepted")
+	}
+	for _, setting := range []string{"scale", "tps"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("the error doesn't report the %s: %v", setting, err)
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package console
+
+import (
+	"fmt"
+	"image/color"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/rendering"
+)
+
+const (
+	maxOutputLines = 200
+	maxHistory     = 100
+	visibleLines   = 14
+	lineHeight     = 14
+	padding        = 8
+	// Ticks a held key waits before it repeats, and between two repeats
+	repeatDelay    = 30
+	repeatInterval = 3
+)
+
+var backgroundColor = color.RGBA{0x00, 0x00, 0x00, 0xD0}
+
+// Console is the developer console, a command line over the top of the screen.
+// It is opened with the backquote key, and runs the commands of its registry.
+type Console struct {
+	Registry *Registry
+	Open     bool
+	line     string
+	output   []string
+	history  []string
+	browsing int // Index in the history of the line shown while browsing it, len(history) when not browsing
+	ticks    int // Ticks since the console was opened, for the blinking cursor
+}
+
+// New creates a closed console running the commands of the registry.
+func New(registry *Registry) *Console {
+	return &Console{Registry: registry}
+}
+
+// Toggle opens or closes the console.
+func (console *Console) Toggle() {
+	console.Open = !console.Open
+	console.browsing = len(console.history)
+	console.ticks = 0
+}
+
+// Printf adds a line to the output of the console.
+func (console *Console) Printf(format string, args ...any) {
+	for _, line := range strings.Split(fmt.Sprintf(format, args...), "\n") {
+		console.output = append(console.output, line)
+	}
+	if len(console.output) > maxOutputLines {
+		console.output = console.output[len(console.output)-maxOutputLines:]
+	}
+}
+
+// Clear empties the output of the console.
+func (console *Console) Clear() {
+	console.output = nil
+}
+
+// Run executes a command line, and prints it with its error, if any.
+func (console *Console) Run(line string) {
+	console.Printf("> %s", line)
+	if err := console.Registry.Execute(line); err != nil {
+		console.Printf("%v", err)
+	}
+}
+
+// Update handles the typing while the console is open.
+// Enter runs the line, Up and Down browse the history, Tab completes the line and Escape closes the console.
+func (console *Console) Update() {
+	if !console.Open {
+		return
+	}
+	console.ticks++
+
+	for _, char := range ebiten.AppendInputChars(nil) {
+		// The backquote opens and closes the console
+		if char != '`' {
+			console.line += string(char)
+		}
+	}
+
+	switch {
+	case repeating(ebiten.KeyBackspace) && console.line != "":
+		_, size := utf8.DecodeLastRuneInString(console.line)
+		console.line = console.line[:len(console.line)-size]
+	case inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyNumpadEnter):
+		console.submit()
+	case inpututil.IsKeyJustPressed(ebiten.KeyTab):
+		completed, candidates := console.Registry.Complete(console.line)
+		if len(candidates) > 0 {
+			console.Printf("%s", strings.Join(candidates, "  "))
+		}
+		console.line = completed
+	case repeating(ebiten.KeyArrowUp) && console.browsing > 0:
+		console.browsing--
+		console.line = console.history[console.browsing]
+	case repeating(ebiten.KeyArrowDown) && console.browsing < len(console.history):
+		console.browsing++
+		console.line = ""
+		if console.browsing < len(console.history) {
+			console.line = console.history[console.browsing]
+		}
+	case inpututil.IsKeyJustPressed(ebiten.KeyEscape):
+		console.Open = false
+	}
+}
+
+// submit runs the typed line and adds it to the history.
+func (console *Console) submit() {
+	line := strings.TrimSpace(console.line)
+	console.line = ""
+	if line == "" {
+		return
+	}
+	if len(console.history) == 0 || console.history[len(console.history)-1] != line {
+		console.history = append(console.history, line)
+	}
+	if len(console.history) > maxHistory {
+		console.history = console.history[len(console.history)-maxHistory:]
+	}
+	console.browsing = len(console.history)
+	console.Run(line)
+}
+
+// repeating reports whether the key was just pressed, or is held long enough to repeat.
+func repeating(key ebiten.Key) bool {
+	duration := inpututil.KeyPressDuration(key)
+	return duration == 1 || (duration >= repeatDelay && (duration-repeatDelay)%repeatInterval == 0)
+}
+
+// Draw draws the last lines of the output and the typed line, with a blinking cursor.
+func (console *Console) Draw(screen *ebiten.Image) {
+	if !console.Open {
+		return
+	}
+
+	height := float32((visibleLines+1)*lineHeight + 2*padding)
+	rendering.DrawColoredRect(screen, 0, 0, rendering.ScreenWidth, height, backgroundColor)
+
+	output := console.output[max(len(console.output)-visibleLines, 0):]
+	for i, line := range output {
+		rendering.DrawText(screen, line, padding, float64(padding+i*lineHeight))
+	}
+
+	cursor := ""
+	if console.ticks/30%2 == 0 {
+		cursor = "_"
+	}
+	rendering.DrawText(screen, "> "+console.line+cursor, padding, float64(padding+visibleLines*lineHeight))
+}
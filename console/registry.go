@@ -0,0 +1,140 @@
+package console
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Command is a console command, e.g. "tp 100 200".
+type Command struct {
+	Name  string
+	Usage string // Arguments shown by help, e.g. "<x> <y>"
+	Help  string
+	// Complete returns the candidates of the argument at the index, optional
+	Complete func(index int) []string
+	Run      func(args []string) error
+}
+
+// ErrUsage is returned by the commands called with the wrong arguments, the registry adds the usage to it.
+var ErrUsage = errors.New("wrong arguments")
+
+// Registry holds the commands by name. The console and the startup scripts run the commands of the same registry.
+type Registry struct {
+	commands map[string]*Command
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{commands: map[string]*Command{}}
+}
+
+// Register adds the command, replacing any command with the same name.
+func (registry *Registry) Register(command *Command) {
+	registry.commands[command.Name] = command
+}
+
+// Names returns the names of the commands in alphabetical order.
+func (registry *Registry) Names() []string {
+	names := make([]string, 0, len(registry.commands))
+	for name := range registry.commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Lookup returns the command with the name, or nil if there is none.
+func (registry *Registry) Lookup(name string) *Command {
+	return registry.commands[name]
+}
+
+// Execute runs a command line. Empty lines and lines starting with # do nothing.
+func (registry *Registry) Execute(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil
+	}
+
+	command := registry.commands[fields[0]]
+	if command == nil {
+		return fmt.Errorf("unknown command %q, type help for the list", fields[0])
+	}
+	err := command.Run(fields[1:])
+	if errors.Is(err, ErrUsage) {
+		return fmt.Errorf("usage: %s %s", command.Name, command.Usage)
+	}
+	return err
+}
+
+// RunScript executes the file one line at a time. It stops at the first failing line.
+func (registry *Registry) RunScript(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		if err := registry.Execute(scanner.Text()); err != nil {
+			return fmt.Errorf("%s:%d: %w", path, lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// Complete completes the last word of the line - the command name, or an argument of the command.
+// It returns the completed line, and the candidates when there are several of them.
+func (registry *Registry) Complete(line string) (string, []string) {
+	fields := strings.Fields(line)
+	// A trailing space starts a new word
+	if len(fields) == 0 || strings.HasSuffix(line, " ") {
+		fields = append(fields, "")
+	}
+	last := len(fields) - 1
+
+	var candidates []string
+	if last == 0 {
+		candidates = registry.Names()
+	} else if command := registry.commands[fields[0]]; command != nil && command.Complete != nil {
+		candidates = command.Complete(last - 1)
+	}
+
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(fields[last])) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return line, nil
+	}
+
+	fields[last] = commonPrefix(matches)
+	completed := strings.Join(fields, " ")
+	if len(matches) == 1 {
+		return completed + " ", nil
+	}
+	return completed, matches
+}
+
+// commonPrefix returns the longest prefix shared by all the words, ignoring the case like the matching of Complete.
+// The prefix keeps the case of the first word.
+func commonPrefix(words []string) string {
+	prefix := []rune(words[0])
+	for _, word := range words[1:] {
+		length := 0
+		for _, char := range word {
+			if length == len(prefix) || !strings.EqualFold(string(char), string(prefix[length])) {
+				break
+			}
+			length++
+		}
+		prefix = prefix[:length]
+	}
+	return string(prefix)
+}
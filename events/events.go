@@ -0,0 +1,42 @@
+// Package events is a typed event bus. The gameplay code publishes what happens in the game,
+// and the audio, the HUD, the stats or the logs subscribe to it, so adding a listener doesn't change the publisher.
+package events
+
+import "reflect"
+
+// Bus delivers the published events to the handlers subscribed to their type.
+// The handlers run right away, in the order they subscribed, on the goroutine that publishes.
+type Bus struct {
+	handlers map[reflect.Type][]func(any)
+	all      []func(any)
+}
+
+// NewBus creates a bus without subscribers.
+func NewBus() *Bus {
+	return &Bus{handlers: map[reflect.Type][]func(any){}}
+}
+
+// Subscribe calls the handler with every event of type E published to the bus.
+func Subscribe[E any](bus *Bus, handler func(event E)) {
+	eventType := reflect.TypeFor[E]()
+	bus.handlers[eventType] = append(bus.handlers[eventType], func(event any) { handler(event.(E)) })
+}
+
+// SubscribeAll calls the handler with every event published to the bus, e.g. to log them.
+func (bus *Bus) SubscribeAll(handler func(event any)) {
+	bus.all = append(bus.all, handler)
+}
+
+// Publish delivers the event to its subscribers. Publishing to a nil bus does nothing,
+// so the code that publishes works without anyone listening.
+func Publish[E any](bus *Bus, event E) {
+	if bus == nil {
+		return
+	}
+	for _, handler := range bus.handlers[reflect.TypeFor[E]()] {
+		handler(event)
+	}
+	for _, handler := range bus.all {
+		handler(event)
+	}
+}
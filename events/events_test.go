@@ -0,0 +1,51 @@
+package events
+
+import (
+	"slices"
+	"testing"
+)
+
+type purged struct{ name string }
+
+type spared struct{ name string }
+
+func TestPublishInSubscriptionOrder(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+	Subscribe(bus, func(purged) { calls = append(calls, "first") })
+	bus.SubscribeAll(func(any) { calls = append(calls, "all") })
+	Subscribe(bus, func(purged) { calls = append(calls, "second") })
+
+	Publish(bus, purged{"Ghoul"})
+
+	// The handlers of every event run after the ones of the event's type
+	if want := []string{"first", "second", "all"}; !slices.Equal(calls, want) {
+		t.Errorf("got the calls %v, want %v", calls, want)
+	}
+}
+
+func TestPublishToTypeHandlers(t *testing.T) {
+	bus := NewBus()
+	var purges, spares []string
+	var all []any
+	Subscribe(bus, func(event purged) { purges = append(purges, event.name) })
+	Subscribe(bus, func(event spared) { spares = append(spares, event.name) })
+	bus.SubscribeAll(func(event any) { all = append(all, event) })
+
+	Publish(bus, purged{"Ghoul"})
+	Publish(bus, spared{"Citizen"})
+
+	if !slices.Equal(purges, []string{"Ghoul"}) {
+		t.Errorf("the purge handler got %v, want only the purge", purges)
+	}
+	if !slices.Equal(spares, []string{"Citizen"}) {
+		t.Errorf("the spare handler got %v, want only the spare", spares)
+	}
+	if want := []any{purged{"Ghoul"}, spared{"Citizen"}}; !slices.Equal(all, want) {
+		t.Errorf("the handler of every event got %v, want %v", all, want)
+	}
+}
+
+func TestPublishToNilBus(t *testing.T) {
+	Publish(nil, purged{"Ghoul"})
+}
@@ -0,0 +1,3 @@
+module github.com/events
+
+go 1.24.2
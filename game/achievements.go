@@ -0,0 +1,49 @@
+package game
+
+import (
+	"github.com/events"
+	"github.com/gameplay"
+)
+
+// The achievements of the game. Each one is unlocked once per session.
+const (
+	achievementFirstPurge = "The Culling Begins" // Purge the first NPC
+	achievementMerciful   = "Light's Mercy"      // Spare 10 NPCs in a game
+	achievementLevelFive  = "Frostmourne Fed"    // Reach level 5
+	achievementFlawless   = "Untouchable"        // Win a game without taking any damage
+)
+
+// mercifulSpares is the number of NPCs to spare in a game for achievementMerciful.
+const mercifulSpares = 10
+
+// listenForAchievements unlocks the achievements as the events of the game come in.
+func (g *Game) listenForAchievements(bus *events.Bus) {
+	events.Subscribe(bus, func(gameplay.NPCPurged) {
+		g.unlock(achievementFirstPurge)
+	})
+	events.Subscribe(bus, func(event gameplay.NPCSpared) {
+		if event.Spared >= mercifulSpares {
+			g.unlock(achievementMerciful)
+		}
+	})
+	events.Subscribe(bus, func(event gameplay.LevelUp) {
+		if event.Level >= 5 {
+			g.unlock(achievementLevelFive)
+		}
+	})
+	events.Subscribe(bus, func(event gameplay.StateChanged) {
+		if event.To == StatusMap[GameWon] && g.stats.damageTaken == 0 {
+			g.unlock(achievementFlawless)
+		}
+	})
+}
+
+// unlock unlocks the achievement and announces it, unless it was already unlocked in this session.
+func (g *Game) unlock(name string) {
+	if g.achievements[name] {
+		return
+	}
+	g.achievements[name] = true
+	g.Hud.Announce("Achievement unlocked: " + name)
+	logger.Info("achievement unlocked", "name", name)
+}
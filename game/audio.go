@@ -0,0 +1,84 @@
+package game
+
+import (
+	"github.com/actor"
+	"github.com/config"
+	"github.com/events"
+	"github.com/gameplay"
+	"github.com/player"
+	"github.com/rendering"
+	"github.com/sound"
+)
+
+// newSound creates the audio manager with the volumes of the settings.
+func (g *Game) newSound() *sound.Manager {
+	manager := sound.NewManager(g.Settings.Volume, g.Settings.MusicVolume, g.Settings.EffectsVolume)
+	rendering.OnPress = func() { manager.PlayEffect(sound.Click, 0) }
+	return manager
+}
+
+// newVolumeSliders creates the music and effects sliders, shared by the main menu and the pause menu.
+func (g *Game) newVolumeSliders() {
+	g.musicSlider = &rendering.Slider{Text: "Music", Value: g.Settings.MusicVolume, OnChange: func(volume float64) {
+		g.Settings.MusicVolume = volume
+		g.saveVolumes()
+	}}
+	g.effectsSlider = &rendering.Slider{Text: "Effects", Value: g.Settings.EffectsVolume, OnChange: func(volume float64) {
+		g.Settings.EffectsVolume = volume
+		g.saveVolumes()
+	}}
+}
+
+// saveVolumes applies the volumes and persists them to the settings file.
+func (g *Game) saveVolumes() {
+	g.Sound.SetVolumes(g.Settings.Volume, g.Settings.MusicVolume, g.Settings.EffectsVolume)
+	err := config.Save(g.Settings.File, map[string]any{
+		"musicVolume":   g.Settings.MusicVolume,
+		"effectsVolume": g.Settings.EffectsVolume,
+	})
+	if err != nil {
+		logger.Error("saving the volumes", "path", g.Settings.File, "err", err)
+	}
+}
+
+// musicTrack returns the track of the game state - the menu, the boss or the Stratholme music.
+func (g *Game) musicTrack() string {
+	switch g.State.Status {
+	case StatusMap[GameMenu], StatusMap[GameEnded], StatusMap[GameWon], StatusMap[GameLost]:
+		return sound.MenuMusic
+	}
+	if g.PlayMode == nil {
+		return sound.MenuMusic
+	}
+	for _, boss := range g.ctx.Bosses {
+		if boss.Actor.Draw && !boss.Defeated() {
+			return sound.BossMusic
+		}
+	}
+	return sound.StratholmeMusic
+}
+
+// listenForSounds plays the sound effects of the game's events.
+func (g *Game) listenForSounds(bus *events.Bus) {
+	events.Subscribe(bus, func(event gameplay.AbilityCast) {
+		if event.Ability == player.DeathAndDecayType {
+			g.playEffectAt(sound.DeathAndDecay, g.purgerActor)
+		}
+	})
+	events.Subscribe(bus, func(event gameplay.NPCPurged) {
+		g.playEffectAt(sound.Purge, event.NPC)
+		g.playEffectAt(sound.NPCDeath, event.NPC)
+	})
+	events.Subscribe(bus, func(event gameplay.BossDefeated) {
+		g.playEffectAt(sound.NPCDeath, event.Boss.Actor)
+	})
+	events.Subscribe(bus, func(gameplay.LevelUp) {
+		g.playEffectAt(sound.LevelUp, g.purgerActor)
+	})
+}
+
+// playEffectAt plays the sound effect panned to the actor's position on the screen.
+func (g *Game) playEffectAt(name string, source *actor.Actor) {
+	center := source.GetBoundingRect().Center()
+	g.Sound.PlayEffect(name, sound.Pan(center[0], ScreenWidthFloat))
+}
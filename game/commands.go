@@ -0,0 +1,380 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/actor"
+	"github.com/console"
+	"github.com/gameplay"
+	"github.com/logging"
+	"github.com/rendering"
+)
+
+// errNoGame is returned by the commands that need a running game.
+var errNoGame = errors.New("no game is running, start one with setmode")
+
+// variables are the player's values that can be changed with the set command.
+var variables = []string{"speed", "health", "mana", "level"}
+
+// newConsole creates the developer console with the commands acting on the game.
+func (g *Game) newConsole() *console.Console {
+	registry := console.NewRegistry()
+	devConsole := console.New(registry)
+
+	registry.Register(&console.Command{
+		Name:     "help",
+		Usage:    "[command]",
+		Help:     "list the commands, or describe one",
+		Complete: func(int) []string { return registry.Names() },
+		Run: func(args []string) error {
+			names := registry.Names()
+			if len(args) > 0 {
+				names = args
+			}
+			for _, name := range names {
+				command := registry.Lookup(name)
+				if command == nil {
+					return fmt.Errorf("unknown command %q", name)
+				}
+				devConsole.Printf("%s - %s", strings.TrimSpace(command.Name+" "+command.Usage), command.Help)
+			}
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name: "clear",
+		Help: "clear the console",
+		Run: func([]string) error {
+			devConsole.Clear()
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name:     "spawn",
+		Usage:    "<archetype> <x> <y>",
+		Help:     "spawn an NPC of one of the mode's archetypes",
+		Complete: g.completeArchetypes,
+		Run:      g.spawnCommand,
+	})
+	registry.Register(&console.Command{
+		Name:     "kill",
+		Usage:    "all|target",
+		Help:     "kill all the NPCs, or the player's target",
+		Complete: func(int) []string { return []string{"all", "target"} },
+		Run:      g.killCommand,
+	})
+	registry.Register(&console.Command{
+		Name: "god",
+		Help: "toggle the player's invulnerability",
+		Run: func([]string) error {
+			if !g.running() {
+				return errNoGame
+			}
+			g.player.God = !g.player.God
+			devConsole.Printf("god mode %s", onOff(g.player.God))
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name:  "setmode",
+		Usage: "<mode>",
+		Help:  "start a new game in the mode",
+		Complete: func(int) []string {
+			modes := make([]string, 0, len(gameplay.Modes()))
+			for _, info := range gameplay.Modes() {
+				modes = append(modes, strconv.Itoa(info.ID))
+			}
+			return modes
+		},
+		Run: g.setModeCommand,
+	})
+	registry.Register(&console.Command{
+		Name:  "state",
+		Usage: "<status>",
+		Help:  "set the game status, e.g. Paused",
+		Complete: func(int) []string {
+			names := make([]string, 0, len(StatusMap))
+			for name := range StatusMap {
+				names = append(names, string(name))
+			}
+			sort.Strings(names)
+			return names
+		},
+		Run: g.stateCommand,
+	})
+	registry.Register(&console.Command{
+		Name:  "tp",
+		Usage: "<x> <y>",
+		Help:  "teleport the player",
+		Run: func(args []string) error {
+			if !g.running() {
+				return errNoGame
+			}
+			position, err := parsePoint(args)
+			if err != nil {
+				return err
+			}
+			g.purgerActor.Position = position
+			g.player.WalkTo(nil)
+			g.purgerActor.SetLimitBounds(ScreenWidthFloat, ScreenHeightFloat)
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name:     "set",
+		Usage:    "<" + strings.Join(variables, "|") + "> <value>",
+		Help:     "change one of the player's values",
+		Complete: func(index int) []string { return firstArgument(index, variables) },
+		Run:      g.setCommand,
+	})
+	registry.Register(&console.Command{
+		Name: "debug",
+		Help: "toggle the debug overlay",
+		Run: func([]string) error {
+			g.Debug = !g.Debug
+			devConsole.Printf("debug overlay %s", onOff(g.Debug))
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name: "lighting",
+		Help: "toggle the lighting and the fog-of-war",
+		Run: func([]string) error {
+			g.Lighting = !g.Lighting
+			devConsole.Printf("lighting %s", onOff(g.Lighting))
+			return nil
+		},
+	})
+	registry.Register(&console.Command{
+		Name:     "log",
+		Usage:    "[<subsystem> <level>]",
+		Help:     "show the log levels, or change the level of a subsystem",
+		Complete: func(index int) []string { return firstArgument(index, logging.Subsystems) },
+		Run: func(args []string) error {
+			switch len(args) {
+			case 0:
+			case 2:
+				if err := logging.SetLevel(args[0], args[1]); err != nil {
+					return err
+				}
+			default:
+				return console.ErrUsage
+			}
+			devConsole.Printf("log %s", logging.Spec())
+			return nil
+		},
+	})
+
+	return devConsole
+}
+
+// runScript runs the console commands of the file.
+func (g *Game) runScript(path string) {
+	if err := g.console.Registry.RunScript(path); err != nil {
+		logger.Error("running the script", "err", err)
+		g.console.Printf("%v", err)
+		return
+	}
+	logger.Info("script done", "path", path)
+}
+
+// running reports whether there is a game to act on.
+func (g *Game) running() bool {
+	return g.player != nil && g.State.Status != StatusMap[GameMenu]
+}
+
+func (g *Game) completeArchetypes(index int) []string {
+	if index != 0 || g.PlayMode == nil {
+		return nil
+	}
+	names := []string{}
+	for _, archetype := range g.ctx.Archetypes {
+		names = append(names, archetype.Name)
+	}
+	return names
+}
+
+func (g *Game) spawnCommand(args []string) error {
+	if !g.running() {
+		return errNoGame
+	}
+	if len(args) != 3 {
+		return console.ErrUsage
+	}
+	position, err := parsePoint(args[1:])
+	if err != nil {
+		return err
+	}
+	for _, archetype := range g.ctx.Archetypes {
+		if strings.EqualFold(archetype.Name, args[0]) {
+			// The console runs between two ticks, so the NPC can join the game right away
+			archetype.NewNPC(g.ctx.Entities, position)
+			g.ctx.Entities.Flush()
+			return nil
+		}
+	}
+	return fmt.Errorf("the mode has no archetype %q, pick one of %s", args[0], strings.Join(g.completeArchetypes(0), ", "))
+}
+
+// killCommand kills the NPCs, and takes all the health of the bosses.
+func (g *Game) killCommand(args []string) error {
+	if !g.running() {
+		return errNoGame
+	}
+	if len(args) != 1 {
+		return console.ErrUsage
+	}
+
+	victims := g.ctx.NPCs()
+	switch args[0] {
+	case "all":
+	case "target":
+		if !g.player.HasTarget() {
+			return errors.New("the player has no target")
+		}
+		victims = []*actor.Actor{g.player.Target}
+	default:
+		return console.ErrUsage
+	}
+
+	bosses := g.ctx.Bosses
+	for _, npc := range victims {
+		if !npc.Present() {
+			continue
+		}
+		killed := false
+		for _, boss := range bosses {
+			if boss.Actor == npc {
+				boss.TakeDamage(boss.Health)
+				killed = true
+			}
+		}
+		if !killed {
+			npc.Die()
+		}
+	}
+	return nil
+}
+
+func (g *Game) setModeCommand(args []string) error {
+	if len(args) != 1 {
+		return console.ErrUsage
+	}
+	mode, err := strconv.Atoi(args[0])
+	if err != nil {
+		return console.ErrUsage
+	}
+	if _, ok := gameplay.Mode(mode); !ok {
+		return fmt.Errorf("mode %d doesn't exist", mode)
+	}
+
+	g.modeList.Select(modeIndex(mode))
+	g.quitToMenu()
+	g.startGame()
+	return nil
+}
+
+func (g *Game) stateCommand(args []string) error {
+	if len(args) != 1 {
+		return console.ErrUsage
+	}
+	for name, status := range StatusMap {
+		if !strings.EqualFold(string(name), args[0]) {
+			continue
+		}
+		switch {
+		case name == gameplay.AwaitingUser:
+			return errors.New("the game waits for the player only after an encounter")
+		case name == gameplay.GameMenu:
+			g.quitToMenu()
+		case !g.running():
+			return errNoGame
+		default:
+			g.State.Status = status
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown status %q", args[0])
+}
+
+func (g *Game) setCommand(args []string) error {
+	if !g.running() {
+		return errNoGame
+	}
+	if len(args) != 2 {
+		return console.ErrUsage
+	}
+	if args[0] == "level" {
+		return g.setLevel(args[1])
+	}
+	value, err := strconv.ParseFloat(args[1], 64)
+	if err != nil || value < 0 {
+		return fmt.Errorf("the value must be a positive number, got %q", args[1])
+	}
+
+	switch args[0] {
+	case "speed":
+		// The speed at level 1, so the modes that slow the player down go back to it
+		g.player.BaseSpeed = value
+		g.purgerActor.Speed = g.player.MoveSpeed()
+	case "health":
+		g.player.Health = int(value)
+		g.player.MaxHealth = max(g.player.MaxHealth, g.player.Health)
+	case "mana":
+		g.player.Mana = int(value)
+		g.player.MaxMana = max(g.player.MaxMana, g.player.Mana)
+	default:
+		return fmt.Errorf("unknown variable %q, pick one of %s", args[0], strings.Join(variables, ", "))
+	}
+	return nil
+}
+
+// setLevel sets the player's level, which is a whole number from 1.
+func (g *Game) setLevel(arg string) error {
+	level, err := strconv.Atoi(arg)
+	if err != nil || level < 1 {
+		return fmt.Errorf("the level must be a whole number from 1, got %q", arg)
+	}
+	// Leveling up grows the stats, a lower level only changes the number
+	for g.player.Level < level {
+		g.player.LevelUp()
+	}
+	g.player.Level = level
+	g.player.XP = 0
+	return nil
+}
+
+// parsePoint parses the x and y arguments of a point on the screen.
+func parsePoint(args []string) ([2]float64, error) {
+	if len(args) != 2 {
+		return [2]float64{}, console.ErrUsage
+	}
+	x, errX := strconv.ParseFloat(args[0], 64)
+	y, errY := strconv.ParseFloat(args[1], 64)
+	if errX != nil || errY != nil {
+		return [2]float64{}, console.ErrUsage
+	}
+	if x < 0 || x > rendering.ScreenWidth || y < 0 || y > rendering.ScreenHeight {
+		return [2]float64{}, fmt.Errorf("the point must be on the screen, %dx%d", rendering.ScreenWidth, rendering.ScreenHeight)
+	}
+	return [2]float64{x, y}, nil
+}
+
+// firstArgument returns the candidates for the first argument of a command.
+func firstArgument(index int, candidates []string) []string {
+	if index != 0 {
+		return nil
+	}
+	return candidates
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
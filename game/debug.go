@@ -0,0 +1,140 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/actor"
+	"github.com/gameplay"
+	"github.com/player"
+	"github.com/rendering"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+var (
+	debugRectColor       = color.RGBA{0xFF, 0x30, 0x30, 0xFF}
+	debugAoEColor        = color.RGBA{0x30, 0xC0, 0xFF, 0xFF}
+	debugPathColor       = color.RGBA{0xFF, 0xFF, 0x30, 0xFF}
+	debugBackgroundColor = color.RGBA{0x00, 0x00, 0x00, 0xB0}
+)
+
+const (
+	debugPanelWidth = 270
+	debugLineHeight = 14
+	debugIdLength   = 8 // Characters of the actor ids shown under the actors
+)
+
+// drawDebugOverlay draws the debug information over everything else.
+func (g *Game) drawDebugOverlay(screen *ebiten.Image) {
+	if !g.Debug {
+		return
+	}
+
+	if g.player != nil && g.State.Status != StatusMap[GameMenu] {
+		g.drawDebugActors(screen)
+	}
+
+	lines := []string{
+		fmt.Sprintf("FPS %.0f  TPS %.0f", ebiten.ActualFPS(), ebiten.ActualTPS()),
+		"Status: " + statusName(g.State.Status),
+	}
+	if g.PlayMode != nil {
+		lines = append(lines, g.debugStateLines()...)
+	}
+
+	x := float32(rendering.ScreenWidth - debugPanelWidth - 10)
+	y := float32(rendering.ScreenHeight - 10 - len(lines)*debugLineHeight)
+	rendering.DrawColoredRect(screen, x-6, y-6, debugPanelWidth+12, float32(len(lines)*debugLineHeight)+8, debugBackgroundColor)
+	for i, line := range lines {
+		rendering.DrawText(screen, line, float64(x), float64(y)+float64(i*debugLineHeight))
+	}
+}
+
+// debugStateLines describes the fields of the game state.
+func (g *Game) debugStateLines() []string {
+	state := g.State
+	target := "none"
+	if g.player != nil && g.player.HasTarget() {
+		target = g.player.Target.Name
+	}
+	info, _ := gameplay.Mode(g.GameMode)
+	return []string{
+		fmt.Sprintf("Mode: %s", info.Name),
+		fmt.Sprintf("Time: %.1fs  Left: %.1fs", state.TimeElapsed, state.TimeLeft),
+		fmt.Sprintf("Wave: %d/%d  Next: %.1fs", state.Wave, state.WaveCount, state.NextWaveIn),
+		fmt.Sprintf("Purged: %d  Spared: %d", state.PurgedCount, state.SparedCount),
+		fmt.Sprintf("NPCs: %d  Target: %s", len(g.ctx.NPCs()), target),
+		fmt.Sprintf("Won: %t  Lost: %t", state.Won, state.Lost),
+	}
+}
+
+// drawDebugActors outlines the actors and the abilities, and draws the NPC paths.
+func (g *Game) drawDebugActors(screen *ebiten.Image) {
+	drawDebugActor(screen, g.purgerActor)
+	for _, npc := range g.ctx.NPCs() {
+		if !npc.Draw {
+			continue
+		}
+		drawDebugActor(screen, npc)
+		drawDebugPath(screen, npc)
+	}
+
+	for _, ability := range g.player.Abilities {
+		rect := ability.Actor.GetBoundingRect()
+		if ability.Type == player.DeathAndDecayType {
+			// The AoE hits everything in the circle inscribed in its texture, see actor.CollidesWithAbility
+			center := rect.Center()
+			rendering.DrawCircleOutline(screen, float32(center[0]), float32(center[1]), float32(rect.Width/2), debugAoEColor)
+			continue
+		}
+		rendering.DrawOutline(screen, float32(rect.PositionX), float32(rect.PositionY), float32(rect.Width), float32(rect.Height), debugAoEColor)
+	}
+
+	for _, boss := range g.ctx.Bosses {
+		for _, telegraph := range boss.Telegraphs {
+			attack := telegraph.Attack
+			x, y := float32(telegraph.Position[0]), float32(telegraph.Position[1])
+			if attack.Shape == gameplay.BoxTelegraph {
+				rendering.DrawOutline(screen, x-float32(attack.Width/2), y-float32(attack.Height/2), float32(attack.Width), float32(attack.Height), debugAoEColor)
+			} else {
+				rendering.DrawCircleOutline(screen, x, y, float32(attack.Radius), debugAoEColor)
+			}
+		}
+	}
+}
+
+// drawDebugActor outlines the actor's bounding rect and writes the start of its id under it.
+func drawDebugActor(screen *ebiten.Image, gameActor *actor.Actor) {
+	rect := gameActor.GetBoundingRect()
+	rendering.DrawOutline(screen, float32(rect.PositionX), float32(rect.PositionY), float32(rect.Width), float32(rect.Height), debugRectColor)
+	id := gameActor.Id
+	if len(id) > debugIdLength {
+		id = id[:debugIdLength]
+	}
+	rendering.DrawText(screen, id, rect.PositionX, rect.PositionY+rect.Height+2)
+}
+
+// drawDebugPath draws a line through the waypoints of the NPC, or to its patrol target.
+func drawDebugPath(screen *ebiten.Image, npc *actor.Actor) {
+	from := npc.Position
+	waypoints := npc.Path
+	if len(waypoints) == 0 {
+		waypoints = [][2]float64{npc.PatrolTarget()}
+	}
+	for _, waypoint := range waypoints {
+		rendering.DrawLine(screen, float32(from[0]), float32(from[1]), float32(waypoint[0]), float32(waypoint[1]), debugPathColor)
+		from = waypoint
+	}
+	rendering.DrawCircleOutline(screen, float32(from[0]), float32(from[1]), 3, debugPathColor)
+}
+
+// statusName returns the name of a game status, e.g. "Started".
+func statusName(status int) string {
+	for name, value := range StatusMap {
+		if value == status {
+			return string(name)
+		}
+	}
+	return fmt.Sprint(status)
+}
@@ -0,0 +1,145 @@
+package game
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/events"
+	"github.com/gameplay"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+// maxParticles is the size of the particle pool.
+const maxParticles = 3000
+
+// slamShakeDuration is the number of seconds the camera shakes when a boss attack lands.
+const slamShakeDuration = 0.4
+
+// The particle effects of the game.
+var (
+	// deathAndDecayMist rises from the whole area of the AoE while it lasts
+	deathAndDecayMist = &rendering.EmitterConfig{
+		Rate:      90,
+		Lifetime:  [2]float64{0.6, 1.2},
+		Speed:     [2]float64{5, 20},
+		Direction: -math.Pi / 2,
+		Spread:    math.Pi / 2,
+		Gravity:   -15,
+		Size:      [2]float64{10, 22},
+		Colors:    []color.RGBA{{0x10, 0x50, 0x10, 0x50}, {0x30, 0x90, 0x20, 0x60}, {0x00, 0x00, 0x00, 0x00}},
+		Additive:  true,
+		Layer:     rendering.LayerGroundEffects,
+	}
+	// deathBurst bursts out of the purged NPCs and the defeated bosses
+	deathBurst = &rendering.EmitterConfig{
+		Burst:    40,
+		Lifetime: [2]float64{0.3, 0.7},
+		Speed:    [2]float64{40, 140},
+		Spread:   2 * math.Pi,
+		Gravity:  120,
+		Area:     6,
+		Size:     [2]float64{6, 2},
+		Colors:   []color.RGBA{{0x90, 0x10, 0x10, 0xFF}, {0x40, 0x00, 0x00, 0x80}},
+		Layer:    rendering.LayerProjectiles,
+	}
+	// levelUpSparkles swirl up around the player for a second
+	levelUpSparkles = &rendering.EmitterConfig{
+		Rate:      80,
+		Duration:  1,
+		Lifetime:  [2]float64{0.4, 0.9},
+		Speed:     [2]float64{20, 60},
+		Direction: -math.Pi / 2,
+		Spread:    math.Pi / 3,
+		Area:      18,
+		Size:      [2]float64{5, 1},
+		Colors:    []color.RGBA{{0xFF, 0xF0, 0x90, 0xFF}, {0xFF, 0xC0, 0x20, 0x00}},
+		Additive:  true,
+		Layer:     rendering.LayerProjectiles,
+	}
+	// deathCoilTrail follows the Death Coil to its target
+	deathCoilTrail = &rendering.EmitterConfig{
+		Rate:     120,
+		Lifetime: [2]float64{0.2, 0.4},
+		Speed:    [2]float64{0, 15},
+		Spread:   2 * math.Pi,
+		Area:     3,
+		Size:     [2]float64{8, 2},
+		Colors:   []color.RGBA{{0x40, 0xC0, 0x40, 0xC0}, {0x00, 0x30, 0x00, 0x00}},
+		Additive: true,
+		Layer:    rendering.LayerProjectiles,
+	}
+	// burstOfLightTrail follows the Burst of Light to its target
+	burstOfLightTrail = &rendering.EmitterConfig{
+		Rate:     120,
+		Lifetime: [2]float64{0.2, 0.4},
+		Speed:    [2]float64{0, 15},
+		Spread:   2 * math.Pi,
+		Area:     3,
+		Size:     [2]float64{8, 2},
+		Colors:   []color.RGBA{{0xFF, 0xE0, 0x80, 0xC0}, {0x30, 0x28, 0x00, 0x00}},
+		Additive: true,
+		Layer:    rendering.LayerProjectiles,
+	}
+)
+
+// resetWorldTracking starts tracking the changes of a new game.
+func (g *Game) resetWorldTracking() {
+	g.abilityEffects = map[*player.Ability]*rendering.Emitter{}
+	g.Particles.Clear()
+}
+
+// listenForEffects starts the particle effects and the camera shakes of the game's events.
+func (g *Game) listenForEffects(bus *events.Bus) {
+	events.Subscribe(bus, func(event gameplay.NPCPurged) {
+		g.Particles.Emit(deathBurst, event.NPC.GetBoundingRect().Center())
+	})
+	events.Subscribe(bus, func(event gameplay.BossDefeated) {
+		g.Particles.Emit(deathBurst, event.Boss.Actor.GetBoundingRect().Center())
+	})
+	events.Subscribe(bus, func(event gameplay.BossAttackLanded) {
+		if event.Attack.Shake > 0 {
+			rendering.Effects.Shake(event.Attack.Shake, slamShakeDuration)
+		}
+	})
+	events.Subscribe(bus, func(gameplay.LevelUp) {
+		sparkles := g.Particles.Emit(levelUpSparkles, g.purgerActor.GetBoundingRect().Center())
+		sparkles.Follow = func() [2]float64 { return g.purgerActor.GetBoundingRect().Center() }
+	})
+}
+
+// updateAbilityEffects starts and stops the effects of the player's abilities.
+func (g *Game) updateAbilityEffects() {
+	active := map[*player.Ability]bool{}
+	for _, ability := range g.player.Abilities {
+		active[ability] = true
+		if _, ok := g.abilityEffects[ability]; ok {
+			continue
+		}
+
+		projectile := ability.Actor
+		center := projectile.GetBoundingRect().Center()
+		switch ability.Type {
+		case player.DeathAndDecayType:
+			mist := *deathAndDecayMist
+			// The mist covers the circle of the AoE, see actor.CollidesWithAbility
+			mist.Area = projectile.GetBoundingRect().Width / 2
+			g.abilityEffects[ability] = g.Particles.Emit(&mist, center)
+		case player.DeathCoilType, player.BurstOfLightType:
+			trail := deathCoilTrail
+			if ability.Type == player.BurstOfLightType {
+				trail = burstOfLightTrail
+			}
+			emitter := g.Particles.Emit(trail, center)
+			emitter.Follow = func() [2]float64 { return projectile.GetBoundingRect().Center() }
+			g.abilityEffects[ability] = emitter
+		}
+	}
+
+	for ability, emitter := range g.abilityEffects {
+		if !active[ability] {
+			emitter.Stop()
+			delete(g.abilityEffects, ability)
+		}
+	}
+}
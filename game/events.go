@@ -0,0 +1,32 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/events"
+	"github.com/gameplay"
+	"github.com/logging"
+	"github.com/rendering"
+)
+
+// eventLogger logs the events of the game with the gameplay logs.
+var eventLogger = logging.For(logging.Gameplay)
+
+// listen subscribes the game to the events of a new game. The stats come before the achievements.
+func (g *Game) listen(bus *events.Bus) {
+	g.listenForSounds(bus)
+	g.listenForEffects(bus)
+	g.Hud.Listen(bus)
+	g.stats = newStats(bus)
+	g.listenForAchievements(bus)
+
+	events.Subscribe(bus, func(event gameplay.StateChanged) {
+		// Fade the end screen in from black once the play mode ends the game
+		if ended(event.To) && !ended(event.From) {
+			rendering.Effects.FadeIn(transitionDuration)
+		}
+	})
+	bus.SubscribeAll(func(event any) {
+		eventLogger.Debug("event", "type", fmt.Sprintf("%T", event), "event", event)
+	})
+}
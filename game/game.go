@@ -2,15 +2,21 @@ package game
 
 import (
 	_ "image/png"
-	"strconv"
 
 	"github.com/actor"
+	"github.com/config"
+	"github.com/console"
+	"github.com/events"
+	"github.com/logging"
 
 	"github.com/gameplay"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/input"
 	"github.com/player"
 	"github.com/rendering"
+	"github.com/sound"
+	"github.com/ui"
 )
 
 var (
@@ -19,6 +25,8 @@ var (
 	GameStarted  = gameplay.GameStarted
 	GamePaused   = gameplay.GamePaused
 	GameEnded    = gameplay.GameEnded
+	GameWon      = gameplay.GameWon
+	GameLost     = gameplay.GameLost
 	AwaitingUser = gameplay.AwaitingUser
 )
 
@@ -30,133 +38,263 @@ const (
 const ScreenWidthFloat = float64(ScreenWidth)
 const ScreenHeightFloat = float64(ScreenHeight)
 
-var GameModeMap = map[int]string{
-	1: "Invincible",
-	2: "Frostmourne Hungers",
-}
-
 const sampleText = "Press space key to start"
+const selectModeText = "Select the game mode"
+
+var logger = logging.For(logging.Game)
 
 type Game struct {
-	Debug        bool
-	player       *player.Player
-	purgerActor  *actor.Actor
-	NPCActors    []*actor.Actor
-	State        *gameplay.GameState
-	keys         []ebiten.Key
-	GameMode     int
-	PlayMode     gameplay.PlayMode
-	PromptPlayer bool
+	Debug          bool
+	Lighting       bool // Draws the lighting of the play mode, see rendering.Lighting
+	Settings       config.Config
+	player         *player.Player
+	purgerActor    *actor.Actor
+	Hud            *ui.Hud
+	State          *gameplay.GameState
+	Controls       *input.Mapper
+	GameMode       int
+	PlayMode       gameplay.PlayMode
+	ctx            *gameplay.Context // What the play mode works with, nil until the first game starts
+	mainMenu       *rendering.UI
+	pauseMenu      *rendering.UI
+	endMenu        *rendering.UI
+	endTitle       *rendering.Label // Whether the player won or lost, in the mode's words
+	endStats       *rendering.Label
+	controlsMenu   *rendering.UI
+	modeList       *rendering.List
+	modePreview    *rendering.Picture // Preview of the selected mode on the home screen
+	modeDetails    *rendering.Label   // Description of the selected mode on the home screen
+	bindingList    *rendering.List
+	bindingHint    *rendering.Label // How to rebind, or the actions the last rebinding took the input from
+	showBindings   bool             // The controls screen is open over the current menu
+	talentsMenu    *rendering.UI
+	talentList     *rendering.List
+	talentTitle    *rendering.Label // Name of the player's talent tree and the points left
+	talentDetails  *rendering.Label // Description of the selected talent
+	showTalents    bool             // The talents screen is open over the pause menu
+	save           *player.SaveFile // Talent choices kept from game to game
+	rebinding      input.Action     // Action waiting for a new key or button on the controls screen
+	console        *console.Console
+	Sound          *sound.Manager
+	musicSlider    *rendering.Slider
+	effectsSlider  *rendering.Slider
+	Particles      *rendering.ParticleSystem
+	renderQueue    *rendering.RenderQueue
+	lastStatus     int                                    // Status of the game in the last tick, see trackStatus
+	abilityEffects map[*player.Ability]*rendering.Emitter // Effects of the player's abilities, see updateAbilityEffects
+	stats          *stats                                 // What the player did in the current game, see listen
+	achievements   map[string]bool                        // Names of the achievements unlocked in this session
 }
 
-func NewGame(debug bool) *Game {
-	return &Game{
-		Debug: debug,
-		State: &gameplay.GameState{Status: gameplay.StatusMap[gameplay.GameMenu]},
+// NewGame creates the game with the settings, on the home screen with the configured mode selected.
+func NewGame(settings config.Config) *Game {
+	bindings, err := input.LoadBindings(settings.ControlsPath)
+	if err != nil {
+		logger.Warn("loading the controls, using the defaults", "path", settings.ControlsPath, "err", err)
+		bindings = input.DefaultBindings()
+	}
+
+	g := &Game{
+		Debug:    settings.Debug,
+		Lighting: settings.Lighting,
+		Settings: settings,
+		State:    &gameplay.GameState{Status: gameplay.StatusMap[gameplay.GameMenu]},
+		GameMode: settings.Mode,
+		Controls: input.NewMapper(bindings),
+
+		achievements: map[string]bool{},
+	}
+	g.Sound = g.newSound()
+	g.Particles = rendering.NewParticleSystem(maxParticles)
+	g.renderQueue = &rendering.RenderQueue{}
+	g.newVolumeSliders()
+	g.mainMenu = g.newMainMenu()
+	g.pauseMenu = g.newPauseMenu()
+	g.endMenu = g.newEndMenu()
+	g.controlsMenu = g.newControlsMenu()
+	g.talentsMenu = g.newTalentsMenu()
+	g.save = loadSave(settings.SavePath)
+	g.console = g.newConsole()
+	if settings.Script != "" {
+		g.runScript(settings.Script)
 	}
+	return g
 }
 
-func (g *Game) DrawActor(screen *ebiten.Image, actor *actor.Actor) {
+// SubmitActor queues the actor's current frame on the layer.
+func (g *Game) SubmitActor(layer rendering.Layer, actor *actor.Actor) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(actor.Position[0], actor.Position[1])
-	rendering.DrawImageWithMatrix(screen, actor.Image, op, g.Debug)
+	op.ColorScale.ScaleAlpha(float32(actor.Opacity()))
+	rect := actor.GetBoundingRect()
+	depth := rect.PositionY + rect.Height
+	if flash := actor.FlashAmount(); flash > 0 {
+		frame := actor.CurrentFrame()
+		g.renderQueue.Submit(layer, depth, func(screen *ebiten.Image) { rendering.DrawFlashed(screen, frame, op, flash) })
+		return
+	}
+	g.renderQueue.SubmitImage(layer, depth, actor.CurrentFrame(), op)
 }
 
-func (g *Game) SpawnActors(screen *ebiten.Image, actors []*actor.Actor) {
+func (g *Game) SubmitActors(actors []*actor.Actor) {
 	for _, actor := range actors {
 		if !actor.Draw {
 			continue
 		}
-		g.DrawActor(screen, actor)
+		g.SubmitActor(rendering.LayerActors, actor)
 	}
 }
 
-func (g *Game) DrawPlayer(screen *ebiten.Image) {
-	g.DrawActor(screen, g.purgerActor)
+func (g *Game) InitHomeScreen(screen *ebiten.Image) {
+	g.mainMenu.Draw(screen)
 }
 
-func (g *Game) PurgedCountStr() string {
-	return "Purged: " + strconv.Itoa(g.State.PurgedCount)
-}
-func (g *Game) SparedCountStr() string {
-	return "Spared: " + strconv.Itoa(g.State.SparedCount)
+// selectGameMode selects the game mode matching the pressed number key, if there is one.
+func (g *Game) selectGameMode() {
+	for index, info := range gameplay.Modes() {
+		if info.ID >= 1 && info.ID <= 9 && inpututil.IsKeyJustPressed(ebiten.Key1+ebiten.Key(info.ID-1)) {
+			g.modeList.Select(index)
+		}
+	}
 }
 
-func (g *Game) InitHomeScreen(screen *ebiten.Image) {
-	rendering.DrawCenteredText(screen, sampleText, ScreenWidth/2, ScreenHeight/2)
+// modeIndex returns the position of the mode in the mode list, or 0 if there is no such mode.
+func modeIndex(id int) int {
+	for index, info := range gameplay.Modes() {
+		if info.ID == id {
+			return index
+		}
+	}
+	return 0
 }
 
-func (g *Game) InitKillFeed(screen *ebiten.Image) {
-	rendering.DrawText(screen, g.PurgedCountStr(), ScreenWidth-100, 0)
-	rendering.DrawText(screen, g.SparedCountStr(), ScreenWidth-100, 20)
+// startGame creates the selected play mode and enters it.
+func (g *Game) startGame() {
+	g.exitGame()
+	g.PlayMode = gameplay.NewPlayMode(g.GameMode)
+	g.ctx = &gameplay.Context{
+		State:    g.State,
+		Controls: g.Controls,
+		Entities: actor.NewManager(),
+		Events:   events.NewBus(),
+		XPCurve:  player.XPCurve{Base: g.Settings.XPBase, Growth: g.Settings.XPGrowth},
+		Builds:   g.save.Talents,
+	}
+	g.PlayMode.Enter(g.ctx)
+	g.player = g.ctx.Player
+	g.purgerActor = g.player.Actor
+	g.Hud = ui.NewHud(g.ctx.Hints, g.Controls)
+	g.listen(g.ctx.Events)
+	g.resetWorldTracking()
+	g.State.Status = StatusMap[GameStarted]
+	info, _ := gameplay.Mode(g.GameMode)
+	logger.Info("game started", "mode", info.Name)
 }
 
-func (g *Game) removeHiddenActors() {
-	NPCActorsCopy := make([]*actor.Actor, 0, len(g.NPCActors)) // Pre-allocate for efficiency
-	for _, npc := range g.NPCActors {
-		if npc.Draw {
-			NPCActorsCopy = append(NPCActorsCopy, npc)
-		}
+// DrawTargetRing draws the selection ring around the player's target, under the actors.
+func (g *Game) DrawTargetRing(screen *ebiten.Image) {
+	if !g.player.HasTarget() {
+		return
 	}
-	g.NPCActors = NPCActorsCopy
+	rect := g.player.Target.GetBoundingRect()
+	center := rect.Center()
+	rendering.DrawSelectionRing(screen, float32(center[0]), float32(center[1]), float32(max(rect.Width, rect.Height)/2+4))
 }
 
 func (g *Game) SetupCommonGameComponents(screen *ebiten.Image) {
-	g.InitKillFeed(screen)
-	g.DrawPlayer(screen)
-	g.SpawnActors(screen, g.NPCActors)
-	g.SpawnPlayerAbilities(screen)
+	queue := g.renderQueue
+	queue.Offset = rendering.Effects.ShakeOffset()
+	queue.Lighting = nil
+	if g.Lighting {
+		queue.Lighting = g.ctx.Lighting
+	}
+	queue.Submit(rendering.LayerGround, 0, func(screen *ebiten.Image) { g.PlayMode.Draw(screen, g.ctx) })
+	queue.Submit(rendering.LayerGroundEffects, 0, g.DrawTargetRing)
+	g.SubmitPlayerAbilities()
+	g.SubmitActors(g.ctx.Entities.All())
+	g.Particles.Submit(queue)
+	queue.Submit(rendering.LayerOverhead, 0, func(screen *ebiten.Image) { g.Hud.DrawNameplates(screen, g.player, g.ctx.NPCs()) })
+	queue.Submit(rendering.LayerHUD, 0, func(screen *ebiten.Image) {
+		g.Hud.Draw(screen, g.State, g.player, g.ctx.Bosses)
+	})
+	queue.Flush(screen)
 }
 
-func (g *Game) SpawnPlayerAbilities(screen *ebiten.Image) {
+// SubmitPlayerAbilities queues the player's abilities and projectiles.
+func (g *Game) SubmitPlayerAbilities() {
 	for _, ability := range g.player.Abilities {
-		g.DrawActor(screen, ability.Actor)
+		layer := rendering.LayerGroundEffects
+		if ability.Target != nil {
+			layer = rendering.LayerProjectiles
+		}
+		g.SubmitActor(layer, ability.Actor)
 	}
 }
 
 // Game lifecycle methods
 func (g *Game) Update() error {
-	g.keys = inpututil.AppendPressedKeys(g.keys[:0])
+	g.Sound.Update(1 / float64(ebiten.TPS()))
+	g.Sound.PlayMusic(g.musicTrack())
+	rendering.Effects.Update(1 / float64(ebiten.TPS()))
+	g.trackStatus()
 
-	// TODO: set default game mode and update based on user choice
-	g.GameMode = 2
-	g.PlayMode = gameplay.NewPlayMode(g.GameMode)
-
-	if g.player == nil {
-		g.player = g.PlayMode.InitPlayer()
-		g.purgerActor = g.player.Actor
+	if g.showBindings {
+		g.updateControlsMenu()
+		return nil
 	}
-
-	if g.NPCActors == nil {
-		g.NPCActors = g.PlayMode.InitNPCs()
+	if g.showTalents {
+		g.talentsMenu.Update()
+		return nil
+	}
+	if g.Controls.JustPressed(input.ToggleConsole) {
+		g.console.Toggle()
+	}
+	// The game stands still while the console is open
+	if g.console.Open {
+		g.console.Update()
+		return nil
+	}
+	if g.Controls.JustPressed(input.ToggleDebug) {
+		g.Debug = !g.Debug
 	}
 
 	switch g.State.Status {
 	case StatusMap[GameMenu]:
-		if ebiten.IsKeyPressed(ebiten.KeySpace) {
-			g.State.Status = StatusMap[GameStarted]
+		g.selectGameMode()
+		g.mainMenu.Update()
+		if g.State.Status == StatusMap[GameMenu] && g.Controls.JustPressed(input.Start) {
+			g.transition(g.startGame)
 		}
 	case StatusMap[GamePaused]:
-		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
-			g.State.Status = StatusMap[GameStarted]
+		if g.Controls.JustPressed(input.ToggleControls) {
+			g.Hud.ToggleControls()
+		}
+		if g.Controls.JustPressed(input.Pause) {
+			g.resumeGame()
 			return nil
 		}
+		g.pauseMenu.Update()
 	case StatusMap[GameStarted]:
-		if inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		if g.Controls.JustPressed(input.Pause) {
 			g.State.Status = StatusMap[GamePaused]
 			return nil
 		}
-		// starts patrolling
-		// set initial actors state
-		g.PlayMode.InitActors(g.NPCActors)
-		if len(g.keys) > 0 {
-			g.PlayMode.HandleKeyboardInput(g.State, g.player, g.NPCActors, g.keys)
-			g.purgerActor.SetLimitBounds(ScreenWidthFloat, ScreenHeightFloat)
+		if g.Controls.JustPressed(input.ToggleControls) {
+			g.Hud.ToggleControls()
+		}
+		g.ctx.Delta = 1 / float64(ebiten.TPS())
+		g.PlayMode.Update(g.ctx)
+		g.Hud.Update(g.ctx.Delta)
+		g.updateAbilityEffects()
+		g.Particles.Update(g.ctx.Delta)
+		if g.ctx.Lighting != nil {
+			g.ctx.Lighting.Update(g.ctx.Delta)
 		}
-		g.removeHiddenActors()
-		g.player.UpdateAbilitiesDurations()
 	case StatusMap[AwaitingUser]:
-		g.PlayMode.HandlePlayerInput(g.State, g.NPCActors, g.State.Target)
+		g.ctx.Delta = 1 / float64(ebiten.TPS())
+		g.PlayMode.Update(g.ctx)
+	case StatusMap[GameEnded], StatusMap[GameWon], StatusMap[GameLost]:
+		g.endMenu.Update()
 	}
 	return nil
 }
@@ -166,17 +304,52 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	switch g.State.Status {
 	case StatusMap[GameMenu]:
 		g.InitHomeScreen(screen)
+		g.drawControlsMenu(screen)
 	case StatusMap[GameStarted]:
 		g.SetupCommonGameComponents(screen)
-		g.PlayMode.CheckGameOverAndUpdateState(g.State, g.NPCActors, g.player)
 	case StatusMap[GamePaused]:
 		g.SetupCommonGameComponents(screen)
-		g.PlayMode.PauseGame(g.State, screen, ScreenWidthFloat, ScreenHeightFloat)
+		rendering.DrawColoredRect(screen, 0, 0, ScreenWidth, ScreenHeight, pauseOverlayColor)
+		g.pauseMenu.Draw(screen)
+		g.drawControlsMenu(screen)
+		g.drawTalentsMenu(screen)
 	case StatusMap[AwaitingUser]:
 		g.SetupCommonGameComponents(screen)
-		g.PlayMode.PropmptPlayer(g.State, g.purgerActor, screen)
-	case StatusMap[GameEnded]:
-		g.PlayMode.EndGame(g.State, screen)
+		g.drawPrompt(screen)
+	case StatusMap[GameEnded], StatusMap[GameWon], StatusMap[GameLost]:
+		g.drawEndScreen(screen)
+	}
+	rendering.Effects.DrawFade(screen)
+	g.drawDebugOverlay(screen)
+	g.console.Draw(screen)
+}
+
+// transitionDuration is the number of seconds of a fade between the screens.
+const transitionDuration = 0.6
+
+// transition fades to black, runs the change of screen and fades back in.
+func (g *Game) transition(change func()) {
+	rendering.Effects.FadeThrough(transitionDuration, change)
+}
+
+// trackStatus publishes the changes of the game status.
+func (g *Game) trackStatus() {
+	if g.State.Status != g.lastStatus && g.PlayMode != nil {
+		events.Publish(g.ctx.Events, gameplay.StateChanged{From: g.lastStatus, To: g.State.Status})
+	}
+	g.lastStatus = g.State.Status
+}
+
+// ended reports whether the status is one of the end screens.
+func ended(status int) bool {
+	return status == StatusMap[GameEnded] || status == StatusMap[GameWon] || status == StatusMap[GameLost]
+}
+
+// exitGame exits the play mode of the current game, if there is one.
+func (g *Game) exitGame() {
+	if g.PlayMode != nil {
+		g.PlayMode.Exit(g.ctx)
+		g.PlayMode = nil
 	}
 }
 
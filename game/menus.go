@@ -0,0 +1,222 @@
+package game
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/gameplay"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+	"github.com/rendering"
+	"github.com/utils"
+)
+
+// newMainMenu builds the home screen - the list of game modes and the start button.
+func (g *Game) newMainMenu() *rendering.UI {
+	modes := gameplay.Modes()
+	items := make([]string, 0, len(modes))
+	for _, info := range modes {
+		items = append(items, "["+strconv.Itoa(info.ID)+"] "+info.Name)
+	}
+	// The previews are loaded on first selection
+	previews := make(map[int]*ebiten.Image, len(modes))
+	preview := func(index int) *ebiten.Image {
+		if image, ok := previews[index]; ok {
+			return image
+		}
+		image, err := rendering.LoadTexture(utils.AssetFile(modes[index].Preview))
+		if err != nil {
+			logger.Error("loading the preview of the mode", "mode", modes[index].Name, "err", err)
+		}
+		previews[index] = image
+		return image
+	}
+
+	g.modePreview = &rendering.Picture{Width: 64, Height: 64}
+	g.modeDetails = &rendering.Label{Centered: true}
+	selectMode := func(index int) {
+		g.GameMode = modes[index].ID
+		g.modePreview.Image = preview(index)
+		g.modeDetails.Text = modes[index].Description
+	}
+	g.modeList = &rendering.List{
+		Items:      items,
+		OnSelect:   selectMode,
+		OnActivate: func(int) { g.transition(g.startGame) },
+	}
+	g.modeList.Select(modeIndex(g.GameMode))
+
+	panel := rendering.NewPanel(
+		&rendering.Label{Text: "Scourge Hunt", Centered: true},
+		&rendering.Label{Text: selectModeText, Centered: true},
+		g.modeList,
+		g.modePreview,
+		g.modeDetails,
+		&rendering.Button{Text: "Start", OnClick: func() { g.transition(g.startGame) }},
+		&rendering.Button{Text: "Controls", OnClick: g.openControlsMenu},
+		g.musicSlider,
+		g.effectsSlider,
+		&rendering.Label{Text: sampleText, Centered: true},
+	)
+	panel.MinWidth = 320
+	return rendering.NewUI(panel)
+}
+
+// newPauseMenu builds the pause screen.
+func (g *Game) newPauseMenu() *rendering.UI {
+	panel := rendering.NewPanel(
+		&rendering.Label{Text: "Game Paused", Centered: true},
+		&rendering.Button{Text: "Resume", OnClick: g.resumeGame},
+		&rendering.Button{Text: "Show Controls", OnClick: func() { g.Hud.ToggleControls() }},
+		&rendering.Button{Text: "Rebind Controls", OnClick: g.openControlsMenu},
+		&rendering.Button{Text: "Talents", OnClick: g.openTalentsMenu},
+		g.musicSlider,
+		g.effectsSlider,
+		&rendering.Button{Text: "Main Menu", OnClick: func() { g.transition(g.quitToMenu) }},
+	)
+	panel.MinWidth = 200
+	return rendering.NewUI(panel)
+}
+
+func (g *Game) resumeGame() {
+	g.State.Status = StatusMap[GameStarted]
+}
+
+// pauseOverlayColor dims the game under the pause menu.
+var pauseOverlayColor = color.RGBA{0x00, 0x00, 0x00, 0x80}
+
+// drawPrompt draws the mode's dialog or prompt text while the game awaits the player.
+func (g *Game) drawPrompt(screen *ebiten.Image) {
+	if g.ctx.Prompt != nil {
+		g.ctx.Prompt.Draw(screen)
+		return
+	}
+	rendering.DrawPlayerPromptAtActorPos(screen, g.State.PromptPlayerText, g.purgerActor.Position)
+}
+
+// newEndMenu builds the end screen - the outcome, the stats and the buttons to go on.
+func (g *Game) newEndMenu() *rendering.UI {
+	g.endTitle = &rendering.Label{Centered: true}
+	g.endStats = &rendering.Label{Centered: true}
+	panel := rendering.NewPanel(
+		g.endTitle,
+		g.endStats,
+		&rendering.Button{Text: "Play Again", OnClick: func() { g.transition(g.startGame) }},
+		&rendering.Button{Text: "Main Menu", OnClick: func() { g.transition(g.quitToMenu) }},
+	)
+	panel.MinWidth = 520
+	return rendering.NewUI(panel)
+}
+
+// drawEndScreen draws the outcome of the game with its stats.
+func (g *Game) drawEndScreen(screen *ebiten.Image) {
+	info, _ := gameplay.Mode(g.GameMode)
+	g.endTitle.Text = info.WinText
+	if g.State.Lost {
+		g.endTitle.Text = info.LossText
+	}
+	g.endStats.Text = g.stats.String()
+	g.endMenu.Draw(screen)
+	// The achievements unlocked by the end of the game are still announced
+	g.Hud.DrawAnnouncements(screen)
+}
+
+// quitToMenu drops the current game and goes back to the home screen.
+func (g *Game) quitToMenu() {
+	g.exitGame()
+	g.State = &gameplay.GameState{Status: StatusMap[GameMenu]}
+}
+
+// newControlsMenu builds the controls screen - the list of actions with their bindings.
+func (g *Game) newControlsMenu() *rendering.UI {
+	g.bindingList = &rendering.List{
+		OnActivate: func(index int) { g.rebinding = input.Actions[index] },
+	}
+	g.refreshBindingList()
+	g.bindingHint = &rendering.Label{Text: rebindHint, Centered: true}
+
+	panel := rendering.NewPanel(
+		&rendering.Label{Text: "Controls", Centered: true},
+		g.bindingList,
+		g.bindingHint,
+		&rendering.Button{Text: "Reset to Defaults", OnClick: g.resetBindings},
+		&rendering.Button{Text: "Back", OnClick: func() { g.showBindings = false }},
+	)
+	panel.MinWidth = 360
+	ui := rendering.NewUI(panel)
+	ui.Modal = true
+	return ui
+}
+
+// rebindHint tells how to rebind an action on the controls screen.
+const rebindHint = "Press Enter on an action, then the new key or button"
+
+func (g *Game) openControlsMenu() {
+	g.showBindings = true
+	g.controlsMenu.FocusFirst()
+}
+
+// updateControlsMenu runs the controls screen while it is open. Esc cancels a rebinding.
+func (g *Game) updateControlsMenu() {
+	if g.rebinding == "" {
+		g.controlsMenu.Update()
+		return
+	}
+
+	key, button, isKey, isButton := input.JustPressedInput()
+	var taken []input.Action
+	switch {
+	case isKey && key == ebiten.KeyEscape:
+	case isKey:
+		taken = g.Controls.RebindKey(g.rebinding, key)
+	case isButton:
+		taken = g.Controls.RebindButton(g.rebinding, button)
+	default:
+		return
+	}
+	g.bindingHint.Text = rebindHint
+	if len(taken) > 0 {
+		names := make([]string, len(taken))
+		for i, action := range taken {
+			names[i] = string(action)
+		}
+		g.bindingHint.Text = "Also unbound from " + strings.Join(names, ", ")
+	}
+	g.rebinding = ""
+	g.saveBindings()
+}
+
+func (g *Game) resetBindings() {
+	g.Controls.Bindings = input.DefaultBindings()
+	g.bindingHint.Text = rebindHint
+	g.saveBindings()
+}
+
+// saveBindings persists the bindings to the config file and shows them on the controls screen.
+func (g *Game) saveBindings() {
+	if err := input.SaveBindings(g.Settings.ControlsPath, g.Controls.Bindings); err != nil {
+		logger.Error("saving the controls", "path", g.Settings.ControlsPath, "err", err)
+	} else {
+		logger.Info("controls saved", "path", g.Settings.ControlsPath)
+	}
+	g.refreshBindingList()
+}
+
+func (g *Game) refreshBindingList() {
+	g.bindingList.Items = g.bindingList.Items[:0]
+	for _, action := range input.Actions {
+		g.bindingList.Items = append(g.bindingList.Items, string(action)+": "+g.Controls.Label(action))
+	}
+}
+
+// drawControlsMenu draws the controls screen over the current menu, if it is open.
+func (g *Game) drawControlsMenu(screen *ebiten.Image) {
+	if !g.showBindings {
+		return
+	}
+	if g.rebinding != "" {
+		g.bindingList.Items[g.bindingList.Selected] = string(g.rebinding) + ": press a key or button..."
+	}
+	g.controlsMenu.Draw(screen)
+}
@@ -0,0 +1,34 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/events"
+	"github.com/gameplay"
+)
+
+// stats counts what the player did in a game, for the end screen and the achievements.
+type stats struct {
+	purged      int
+	spared      int
+	casts       int
+	damageTaken int
+	levelsUp    int
+}
+
+// newStats creates the stats of a new game, counted from its events.
+func newStats(bus *events.Bus) *stats {
+	counts := &stats{}
+	events.Subscribe(bus, func(gameplay.NPCPurged) { counts.purged++ })
+	events.Subscribe(bus, func(gameplay.NPCSpared) { counts.spared++ })
+	events.Subscribe(bus, func(gameplay.AbilityCast) { counts.casts++ })
+	events.Subscribe(bus, func(event gameplay.PlayerDamaged) { counts.damageTaken += event.Amount })
+	events.Subscribe(bus, func(gameplay.LevelUp) { counts.levelsUp++ })
+	return counts
+}
+
+// String sums the stats up in a line.
+func (counts *stats) String() string {
+	return fmt.Sprintf("Purged %d  Spared %d  Casts %d  Damage taken %d  Levels gained %d",
+		counts.purged, counts.spared, counts.casts, counts.damageTaken, counts.levelsUp)
+}
@@ -0,0 +1,123 @@
+package game
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+// loadSave reads the save file. A broken file is logged, and the game starts from an empty save.
+func loadSave(path string) *player.SaveFile {
+	save, err := player.LoadSaveFile(path)
+	if err != nil {
+		logger.Warn("loading the save file, starting a new one", "path", path, "err", err)
+	}
+	return save
+}
+
+// newTalentsMenu builds the talents screen. Picking a talent spends a point on its next rank.
+func (g *Game) newTalentsMenu() *rendering.UI {
+	g.talentTitle = &rendering.Label{Centered: true}
+	g.talentDetails = &rendering.Label{Centered: true}
+	g.talentList = &rendering.List{
+		OnSelect:   g.showTalent,
+		OnActivate: g.learnTalent,
+	}
+
+	panel := rendering.NewPanel(
+		g.talentTitle,
+		g.talentList,
+		g.talentDetails,
+		&rendering.Button{Text: "Reset Talents", OnClick: g.resetTalents},
+		&rendering.Button{Text: "Back", OnClick: func() { g.showTalents = false }},
+	)
+	panel.MinWidth = 360
+	ui := rendering.NewUI(panel)
+	ui.Modal = true
+	return ui
+}
+
+func (g *Game) openTalentsMenu() {
+	g.showTalents = true
+	g.refreshTalentList()
+	g.talentList.Select(0)
+	g.talentsMenu.FocusFirst()
+}
+
+// talents returns the talents of the player's tree, or nil if the hero has none.
+func (g *Game) talents() []*player.Talent {
+	if g.player.TalentTree == nil {
+		return nil
+	}
+	return g.player.TalentTree.Talents
+}
+
+func (g *Game) refreshTalentList() {
+	g.talentList.Items = g.talentList.Items[:0]
+	tree := g.player.TalentTree
+	if tree == nil {
+		g.talentTitle.Text = "No talents for this hero"
+		return
+	}
+	g.talentTitle.Text = tree.Name + " Talents - " + strconv.Itoa(g.player.TalentPoints) + " points"
+	for _, talent := range tree.Talents {
+		g.talentList.Items = append(g.talentList.Items,
+			talent.Name+" "+strconv.Itoa(g.player.TalentRank(talent.ID))+"/"+strconv.Itoa(talent.MaxRank))
+	}
+}
+
+// showTalent shows the description of the selected talent.
+func (g *Game) showTalent(index int) {
+	talents := g.talents()
+	if index >= len(talents) {
+		g.talentDetails.Text = ""
+		return
+	}
+	talent := talents[index]
+	g.talentDetails.Text = talent.Description
+	if g.player.Level < talent.Level {
+		g.talentDetails.Text += "\nNeeds level " + strconv.Itoa(talent.Level)
+	}
+}
+
+func (g *Game) learnTalent(index int) {
+	talents := g.talents()
+	if index >= len(talents) {
+		return
+	}
+	if err := g.player.LearnTalent(talents[index].ID); err != nil {
+		g.talentDetails.Text = err.Error()
+		return
+	}
+	g.saveTalents()
+}
+
+func (g *Game) resetTalents() {
+	if g.player.TalentTree == nil {
+		return
+	}
+	g.player.ResetTalents()
+	g.saveTalents()
+}
+
+// saveTalents persists the build of the player's tree to the save file.
+func (g *Game) saveTalents() {
+	g.save.Talents[g.player.TalentTree.ID] = slices.Clone(g.player.TalentBuild())
+	if err := g.save.Write(g.Settings.SavePath); err != nil {
+		logger.Error("saving the talents", "path", g.Settings.SavePath, "err", err)
+	} else {
+		logger.Info("talents saved", "path", g.Settings.SavePath)
+	}
+	g.refreshTalentList()
+	g.showTalent(g.talentList.Selected)
+}
+
+// drawTalentsMenu draws the talents screen over the pause menu, if it is open.
+func (g *Game) drawTalentsMenu(screen *ebiten.Image) {
+	if g.showTalents {
+		g.talentsMenu.Draw(screen)
+	}
+}
@@ -0,0 +1,275 @@
+package gameplay
+
+import (
+	"image/color"
+	_ "image/png"
+	"math"
+
+	"github.com/actor"
+	"github.com/player"
+
+	"github.com/utils"
+
+	"github.com/rendering"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+)
+
+const (
+	jainaMaxHealth      = 100.0
+	jainaFollowDistance = 60.0 // Jaina stops once she is this close to the player
+	jainaRepathInterval = 0.5  // Seconds between two path searches, the player keeps moving
+	navCellSize         = 20.0
+	scourgeHitDamage    = 10
+)
+
+// Jaina is the friendly actor escorted to Silvermoon.
+type Jaina struct {
+	Actor     *actor.Actor
+	Health    float64
+	MaxHealth float64
+	nextPath  float64 // Game time in seconds of the next path search
+	lastHitAt float64 // Game time in seconds at which Jaina was last hit
+}
+
+// ModeAlreadyDoomed is an escort run from Stratholme to Silvermoon.
+type ModeAlreadyDoomed struct {
+	BasePlayMode
+	Jaina     *Jaina
+	Obstacles []*actor.Actor      // Houses and trees along the road, nobody can walk through them
+	ExitZone  *actor.BoundingRect // The gates of Silvermoon
+	navGrid   *NavGrid            // Navigation grid sized for Jaina
+	lastHitAt float64             // Game time in seconds at which the player was last hit
+}
+
+func init() {
+	RegisterMode(ModeInfo{
+		ID:          4,
+		Name:        "Already Doomed",
+		Description: "Give up on Stratholme and run with Jaina to Silvermoon,\nwhile the Scourge tries to intercept you both.",
+		Preview:     "./assets/purger9000.PNG",
+		WinText:     "Jaina made it safely to Silvermoon!",
+		LossText:    "Jaina has fallen on the road to Silvermoon.",
+		New:         func() PlayMode { return &ModeAlreadyDoomed{} },
+	})
+}
+
+// HandleInput moves the player, stopping at the obstacles, and casts Death and Decay.
+func (playmode *ModeAlreadyDoomed) HandleInput(
+	gameState *GameState,
+	player *player.Player,
+	gameActors []*actor.Actor,
+	controls *input.Mapper) {
+	player.HandleInput(controls.Movement())
+	playmode.blockByObstacles(player.Actor)
+
+	if controls.JustPressed(input.CastAoE) {
+		player.DeathAndDecay()
+	}
+	if controls.JustPressed(input.CastDeathCoil) {
+		player.DeathCoil()
+	}
+}
+
+// InitActors sends the Scourge after the closer one of the player and Jaina.
+func (playmode *ModeAlreadyDoomed) InitActors(npcActors []*actor.Actor) {
+	for _, npcActor := range npcActors {
+		if !npcActor.Present() || npcActor.Path == nil {
+			continue
+		}
+		npcActor.FollowPath()
+		playmode.blockByObstacles(npcActor)
+	}
+}
+
+// blockByObstacles moves the actor back if its last step took it into an obstacle.
+func (playmode *ModeAlreadyDoomed) blockByObstacles(gameActor *actor.Actor) {
+	rect := gameActor.GetBoundingRect()
+	for _, obstacle := range playmode.Obstacles {
+		if rect.Intersects(obstacle.GetBoundingRect()) {
+			gameActor.RollbackPosition()
+			return
+		}
+	}
+}
+
+// FindPath walks the player around the obstacles to a point picked with the mouse.
+func (playmode *ModeAlreadyDoomed) FindPath(from, to [2]float64) [][2]float64 {
+	return playmode.navGrid.FindPath(from, to)
+}
+
+// UpdateWorld moves Jaina and the Scourge, and applies the purges and the hits.
+func (playmode *ModeAlreadyDoomed) UpdateWorld(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	jaina := playmode.Jaina
+	if distance(jaina.Actor, player.Actor) > jainaFollowDistance {
+		if gameState.TimeElapsed >= jaina.nextPath {
+			jaina.Actor.Path = playmode.navGrid.FindPathNear(jaina.Actor.Position, player.Actor.Position)
+			if jaina.Actor.Path == nil {
+				logger.Debug("no path for Jaina to the player", "from", jaina.Actor.Position, "to", player.Actor.Position)
+			}
+			jaina.nextPath = gameState.TimeElapsed + jainaRepathInterval
+		}
+		jaina.Actor.FollowPath()
+	} else {
+		jaina.Actor.Path = nil
+		jaina.Actor.ResetMoveDirection()
+	}
+	jaina.Actor.UpdateAnimation(gameState.Delta)
+	playmode.LandProjectiles(gameState, gameActors, player)
+
+	for _, npcActor := range gameActors {
+		if !npcActor.Draw {
+			continue
+		}
+		// The Scourge moves straight at its prey and gets stuck on the obstacles
+		prey := jaina.Actor
+		if distance(npcActor, player.Actor) < distance(npcActor, jaina.Actor) {
+			prey = player.Actor
+		}
+		npcActor.Path = [][2]float64{prey.Position}
+	}
+
+	for _, ability := range player.Abilities {
+		if ability.Type != deathAndDecayType {
+			continue
+		}
+		for _, npcActor := range gameActors {
+			if npcActor.Draw && npcActor.CollisionEnabled && npcActor.CollidesWithAbility(ability.Actor) {
+				playmode.Purge(gameState, gameActors, npcActor)
+			}
+		}
+	}
+
+	for _, npcActor := range gameActors {
+		if !npcActor.Draw || !npcActor.CollisionEnabled {
+			continue
+		}
+		if jaina.Actor.CollidesWith(npcActor) && gameState.TimeElapsed-jaina.lastHitAt >= contactDamageCooldown {
+			jaina.Health = max(jaina.Health-scourgeHitDamage, 0)
+			jaina.Actor.Flash()
+			jaina.lastHitAt = gameState.TimeElapsed
+		}
+		if player.Actor.CollidesWith(npcActor) && gameState.TimeElapsed-playmode.lastHitAt >= contactDamageCooldown {
+			player.TakeDamage(scourgeHitDamage)
+			playmode.lastHitAt = gameState.TimeElapsed
+		}
+	}
+}
+
+// distance returns the distance between the centers of two actors.
+func distance(actor1, actor2 *actor.Actor) float64 {
+	center1 := actor1.GetBoundingRect().Center()
+	center2 := actor2.GetBoundingRect().Center()
+	return math.Hypot(center1[0]-center2[0], center1[1]-center2[1])
+}
+
+// CheckGameOverAndUpdateState ends the run when Jaina dies or reaches Silvermoon.
+func (playmode *ModeAlreadyDoomed) CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	if playmode.Jaina.Health <= 0 {
+		gameState.Lost = true
+	} else if player.Actor.GetBoundingRect().Intersects(playmode.ExitZone) &&
+		playmode.Jaina.Actor.GetBoundingRect().Intersects(playmode.ExitZone) {
+		gameState.Won = true
+	}
+	playmode.CheckWinCondition(gameState, gameActors, player)
+}
+
+// Enter puts Arthas and Jaina on the road to Silvermoon.
+func (playmode *ModeAlreadyDoomed) Enter(ctx *Context) {
+	playmode.attach(ctx)
+	ctx.Player = playmode.InitPlayer()
+	playmode.InitNPCs()
+	playmode.enter(ctx, playmode.Controls())
+}
+
+// Update runs a tick of the escort.
+func (playmode *ModeAlreadyDoomed) Update(ctx *Context) {
+	playmode.tick(ctx, playmode)
+}
+
+// Draw draws the road to Silvermoon and Jaina's health.
+func (playmode *ModeAlreadyDoomed) Draw(screen *ebiten.Image, ctx *Context) {
+	screen.Fill(color.RGBA{0x3A, 0x5A, 0x2A, 0xFF})
+
+	exit := playmode.ExitZone
+	rendering.DrawColoredRect(screen, float32(exit.PositionX), float32(exit.PositionY), float32(exit.Width), float32(exit.Height), color.RGBA{0xE8, 0xC0, 0x40, 0xFF})
+	rendering.DrawCenteredText(screen, "Silvermoon", exit.PositionX+exit.Width/2, exit.PositionY+exit.Height/2)
+
+	for _, obstacle := range playmode.Obstacles {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(obstacle.Position[0], obstacle.Position[1])
+		screen.DrawImage(obstacle.Image, op)
+	}
+
+	jaina := playmode.Jaina
+	rendering.DrawCenteredText(screen, "Jaina: "+formatHealth(jaina.Health, jaina.MaxHealth), rendering.ScreenWidth/2, 10)
+}
+
+func (playmode *ModeAlreadyDoomed) InitPlayer() *player.Player {
+	// Initialize the player actor
+	playerTexture := rendering.ScaleTexture(rendering.CreateTexture(utils.LoadFile("./assets/arthas.png")), 0.4)
+	playerActor := actor.NewActor([2]float64{20, 400}, playerTexture, 6, "Arthas", true)
+	Animate(playerActor, DefaultClips(playerTexture))
+	deathKnight := player.NewPlayer(playerActor)
+	deathKnight.AbilitySlots = append(deathKnight.AbilitySlots, player.DeathCoilSlot())
+	deathKnight.TalentTree = talentTree(player.DeathKnightTalents)
+	return deathKnight
+}
+
+// InitNPCs builds the road and places Jaina and the Scourge waves.
+func (playmode *ModeAlreadyDoomed) InitNPCs() {
+	obstacleRects := []*actor.BoundingRect{
+		{PositionX: 150, PositionY: 0, Width: 60, Height: 330},
+		{PositionX: 350, PositionY: 200, Width: 60, Height: 350},
+		{PositionX: 550, PositionY: 0, Width: 60, Height: 330},
+		{PositionX: 750, PositionY: 200, Width: 60, Height: 350},
+		{PositionX: 260, PositionY: 440, Width: 40, Height: 40},
+		{PositionX: 460, PositionY: 60, Width: 40, Height: 40},
+		{PositionX: 660, PositionY: 420, Width: 40, Height: 40},
+	}
+	playmode.Obstacles = make([]*actor.Actor, 0, len(obstacleRects))
+	for _, rect := range obstacleRects {
+		obstacleTexture := ebiten.NewImage(int(rect.Width), int(rect.Height))
+		obstacleTexture.Fill(color.RGBA{0x5A, 0x3A, 0x2A, 0xFF})
+		playmode.Obstacles = append(playmode.Obstacles, actor.NewActor([2]float64{rect.PositionX, rect.PositionY}, obstacleTexture, 0, "Obstacle", true))
+	}
+	playmode.ExitZone = &actor.BoundingRect{PositionX: 880, PositionY: 20, Width: 110, Height: 170}
+
+	jainaTexture := rendering.ScaleTexture(rendering.CreateTexture(utils.LoadFile("./assets/purger9000.PNG")), 0.18)
+	jainaActor := actor.NewActor([2]float64{20, 470}, jainaTexture, 5, "Jaina", false)
+	Animate(jainaActor, DefaultClips(jainaTexture))
+	jainaActor.Tag(actor.TagAlly)
+	playmode.Entities.Add(jainaActor)
+	playmode.Jaina = &Jaina{Actor: jainaActor, Health: jainaMaxHealth, MaxHealth: jainaMaxHealth}
+
+	jainaRect := jainaActor.GetBoundingRect()
+	playmode.navGrid = NewNavGrid(rendering.ScreenWidth, rendering.ScreenHeight, navCellSize, obstacleRects, jainaRect.Width, jainaRect.Height)
+
+	// The ghoul sprite is too big for the passages of the road
+	ghoul := &Archetype{Name: "Ghoul", TexturePath: "./assets/scourge.png", Speed: 2, XP: 15}
+	ghoul.texture = rendering.ScaleTexture(ghoul.Texture(), 0.5)
+	playmode.Spawner = &Spawner{
+		SpawnPoints: [][2]float64{
+			{260, 20}, {460, 480}, {660, 20}, {860, 480},
+		},
+		Archetypes: []*Archetype{
+			ghoul,
+			{Name: "Skeleton", TexturePath: "./assets/scv.png", Speed: 3, XP: 20},
+		},
+		WaveInterval: 12,
+		BaseCount:    2,
+		CountGrowth:  1,
+	}
+	playmode.WinCondition = WinCondition{Type: EscortToExit}
+
+	playmode.Spawner.Update(&GameState{}, playmode.Entities)
+}
+
+// Controls adds Death and Decay to the shared controls.
+func (playmode *ModeAlreadyDoomed) Controls() []ControlHint {
+	return append(playmode.BasePlayMode.Controls(),
+		ControlHint{Actions: []input.Action{input.CastAoE}, Description: "Death and Decay"},
+		ControlHint{Actions: []input.Action{input.CastDeathCoil}, Description: "Death Coil the target"},
+	)
+}
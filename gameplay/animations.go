@@ -0,0 +1,73 @@
+package gameplay
+
+import (
+	"github.com/actor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rendering"
+	"github.com/utils"
+)
+
+// SpriteSheetClip is a clip laid out as one row of a sprite sheet.
+type SpriteSheetClip struct {
+	Row           int
+	Frames        int
+	FrameDuration float64 // Seconds each frame is shown for
+	Loop          bool
+}
+
+// SpriteSheet describes a sprite sheet image with equally sized frames and one clip per row.
+type SpriteSheet struct {
+	Path        string
+	FrameWidth  int
+	FrameHeight int
+	Clips       map[string]SpriteSheetClip
+}
+
+// LoadClips loads the sprite sheet and cuts it into its clips.
+func (sheet *SpriteSheet) LoadClips() map[string]*actor.Animation {
+	sheetImage := rendering.CreateTexture(utils.LoadFile(sheet.Path))
+
+	clips := make(map[string]*actor.Animation, len(sheet.Clips))
+	for name, clip := range sheet.Clips {
+		clips[name] = &actor.Animation{
+			Frames:        actor.SliceSpriteSheet(sheetImage, sheet.FrameWidth, sheet.FrameHeight, clip.Row, clip.Frames),
+			FrameDuration: clip.FrameDuration,
+			Loop:          clip.Loop,
+		}
+	}
+	return clips
+}
+
+// DefaultClips makes the standard clips out of a single static sprite, for the actors without a sprite sheet.
+func DefaultClips(image *ebiten.Image) map[string]*actor.Animation {
+	walkRight := []*ebiten.Image{image, rendering.OffsetFrame(image, 0, -2)}
+	flipped := rendering.FlipHorizontal(image)
+	walkLeft := []*ebiten.Image{flipped, rendering.OffsetFrame(flipped, 0, -2)}
+
+	deathFrames := make([]*ebiten.Image, 0, 6)
+	for frame := range 6 {
+		alpha := 1 - float32(frame+1)/6
+		deathFrames = append(deathFrames, rendering.TintFrame(image, alpha, alpha, alpha, alpha))
+	}
+
+	return map[string]*actor.Animation{
+		actor.ClipIdle:      {Frames: []*ebiten.Image{image}, Loop: true},
+		actor.ClipWalkRight: {Frames: walkRight, FrameDuration: 0.15, Loop: true},
+		actor.ClipWalkLeft:  {Frames: walkLeft, FrameDuration: 0.15, Loop: true},
+		actor.ClipWalkUp:    {Frames: walkRight, FrameDuration: 0.2, Loop: true},
+		actor.ClipWalkDown:  {Frames: walkRight, FrameDuration: 0.2, Loop: true},
+		actor.ClipAttack: {
+			Frames:        []*ebiten.Image{rendering.TintFrame(image, 1.6, 1.6, 1.6, 1), image, rendering.TintFrame(image, 1.6, 1.6, 1.6, 1)},
+			FrameDuration: 0.08,
+		},
+		actor.ClipDeath: {Frames: deathFrames, FrameDuration: 0.08},
+	}
+}
+
+// Animate gives the actor an animator with the given clips.
+func Animate(gameActor *actor.Actor, clips map[string]*actor.Animation) {
+	gameActor.Animator = actor.NewAnimator(clips)
+	if idle, ok := clips[actor.ClipIdle]; ok && len(idle.Frames) > 0 {
+		gameActor.Image = idle.Frames[0]
+	}
+}
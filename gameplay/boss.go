@@ -0,0 +1,231 @@
+package gameplay
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/actor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+type TelegraphShape string
+
+const (
+	CircleTelegraph TelegraphShape = "Circle"
+	BoxTelegraph    TelegraphShape = "Box"
+)
+
+type AttackTarget string
+
+const (
+	AtPlayer AttackTarget = "Player" // The attack lands where the player stood when it was cast
+	AtBoss   AttackTarget = "Boss"   // The attack lands around the boss
+)
+
+// BossAttack is a telegraphed ground AoE, shown for WindUp seconds before it lands.
+type BossAttack struct {
+	Name     string
+	Shape    TelegraphShape
+	Target   AttackTarget
+	Radius   float64 // Radius of a CircleTelegraph
+	Width    float64 // Width of a BoxTelegraph
+	Height   float64 // Height of a BoxTelegraph
+	WindUp   float64 // Seconds between the cast and the landing
+	Cooldown float64 // Seconds between the cast and the next attack of the pattern
+	Damage   int
+	Shake    float64 // Pixels the camera shakes when the attack lands, 0 for no shake
+}
+
+// BossPhase is a stage of a boss encounter, from a health threshold on.
+type BossPhase struct {
+	Name            string
+	HealthThreshold float64 // Share of max health at which the phase starts, 1 for the first phase
+	Speed           float64 // Speed of the boss during the phase, 0 keeps the current speed
+	Attacks         []*BossAttack
+	OnEnter         func(boss *Boss, gameState *GameState) // Scripted events when the phase starts, optional
+}
+
+// Telegraph is a cast attack waiting to land.
+type Telegraph struct {
+	Attack   *BossAttack
+	Position [2]float64 // Center of the attack area
+	CastAt   float64    // Game time in seconds at which the attack was cast
+	LandsAt  float64    // Game time in seconds at which the attack lands
+}
+
+// Contains reports whether the actor's center is inside the attack area.
+func (telegraph *Telegraph) Contains(gameActor *actor.Actor) bool {
+	centerX, centerY := actorCenter(gameActor)
+	dx := centerX - telegraph.Position[0]
+	dy := centerY - telegraph.Position[1]
+
+	if telegraph.Attack.Shape == BoxTelegraph {
+		return math.Abs(dx) <= telegraph.Attack.Width/2 && math.Abs(dy) <= telegraph.Attack.Height/2
+	}
+	return dx*dx+dy*dy <= telegraph.Attack.Radius*telegraph.Attack.Radius
+}
+
+// Boss is an enemy with health, phases and scripted attack patterns, built on top of an actor.
+type Boss struct {
+	Actor        *actor.Actor
+	Health       float64
+	MaxHealth    float64
+	Phases       []*BossPhase // Ordered by descending health threshold
+	PhaseIndex   int
+	Telegraphs   []*Telegraph
+	Chase        bool                               // The boss walks towards the player while it's not hidden
+	OnLand       func(attack *BossAttack, hit bool) // Called when an attack lands, hit or not, optional
+	OnDefeat     func()                             // Called when the boss is defeated and starts dying, optional
+	attackIndex  int
+	nextAttackAt float64
+}
+
+// NewBoss creates a boss in its first phase.
+func NewBoss(bossActor *actor.Actor, maxHealth float64, phases []*BossPhase) *Boss {
+	bossActor.Archetype = "Boss"
+	return &Boss{
+		Actor:     bossActor,
+		Health:    maxHealth,
+		MaxHealth: maxHealth,
+		Phases:    phases,
+		Chase:     true,
+	}
+}
+
+// CurrentPhase returns the phase the boss is in.
+func (boss *Boss) CurrentPhase() *BossPhase {
+	return boss.Phases[boss.PhaseIndex]
+}
+
+// Defeated reports whether the boss has no health left.
+func (boss *Boss) Defeated() bool {
+	return boss.Health <= 0
+}
+
+// TakeDamage reduces the boss's health by the given amount, down to 0.
+func (boss *Boss) TakeDamage(amount float64) {
+	boss.Health = max(boss.Health-amount, 0)
+	boss.Actor.Flash()
+}
+
+// Update runs the boss encounter each game tick - the phases, the movement and the attacks.
+func (boss *Boss) Update(gameState *GameState, player *player.Player) {
+	if boss.Defeated() {
+		if !boss.Actor.Dying() && !boss.Actor.Gone() {
+			boss.Actor.Die()
+			if boss.OnDefeat != nil {
+				boss.OnDefeat()
+			}
+		}
+		boss.Telegraphs = nil
+		return
+	}
+
+	boss.updatePhase(gameState)
+	boss.landTelegraphs(gameState, player)
+
+	if !boss.Actor.Draw {
+		return
+	}
+
+	if boss.Chase {
+		bossX, bossY := actorCenter(boss.Actor)
+		playerX, playerY := actorCenter(player.Actor)
+		boss.Actor.MoveDirectionX = playerX - bossX
+		boss.Actor.MoveDirectionY = playerY - bossY
+		boss.Actor.MoveIn([2]float64{boss.Actor.MoveDirectionX, boss.Actor.MoveDirectionY})
+	}
+
+	attacks := boss.CurrentPhase().Attacks
+	if len(attacks) == 0 || gameState.TimeElapsed < boss.nextAttackAt {
+		return
+	}
+	attack := attacks[boss.attackIndex%len(attacks)]
+	boss.attackIndex++
+	boss.nextAttackAt = gameState.TimeElapsed + attack.Cooldown
+	boss.cast(attack, gameState, player)
+}
+
+// updatePhase moves the boss to the next phases whose health threshold has been reached.
+func (boss *Boss) updatePhase(gameState *GameState) {
+	healthShare := boss.Health / boss.MaxHealth
+	for boss.PhaseIndex+1 < len(boss.Phases) && healthShare <= boss.Phases[boss.PhaseIndex+1].HealthThreshold {
+		boss.PhaseIndex++
+		boss.attackIndex = 0
+
+		phase := boss.CurrentPhase()
+		if phase.Speed > 0 {
+			boss.Actor.Speed = phase.Speed
+		}
+		if phase.OnEnter != nil {
+			phase.OnEnter(boss, gameState)
+		}
+	}
+}
+
+// cast places the attack's telegraph on the ground.
+func (boss *Boss) cast(attack *BossAttack, gameState *GameState, player *player.Player) {
+	target := boss.Actor
+	if attack.Target == AtPlayer {
+		target = player.Actor
+	}
+	targetX, targetY := actorCenter(target)
+
+	boss.Telegraphs = append(boss.Telegraphs, &Telegraph{
+		Attack:   attack,
+		Position: [2]float64{targetX, targetY},
+		CastAt:   gameState.TimeElapsed,
+		LandsAt:  gameState.TimeElapsed + attack.WindUp,
+	})
+}
+
+// landTelegraphs damages the player inside the attacks that land.
+func (boss *Boss) landTelegraphs(gameState *GameState, player *player.Player) {
+	pending := make([]*Telegraph, 0, len(boss.Telegraphs))
+	for _, telegraph := range boss.Telegraphs {
+		if gameState.TimeElapsed < telegraph.LandsAt {
+			pending = append(pending, telegraph)
+			continue
+		}
+		hit := telegraph.Contains(player.Actor)
+		if hit {
+			player.TakeDamage(telegraph.Attack.Damage)
+		}
+		if boss.OnLand != nil {
+			boss.OnLand(telegraph.Attack, hit)
+		}
+	}
+	boss.Telegraphs = pending
+}
+
+// DrawTelegraphs draws the areas of the attacks that are about to land.
+func (boss *Boss) DrawTelegraphs(gameState *GameState, screen *ebiten.Image) {
+	for _, telegraph := range boss.Telegraphs {
+		attack := telegraph.Attack
+		progress := 1.0
+		if attack.WindUp > 0 {
+			progress = min((gameState.TimeElapsed-telegraph.CastAt)/attack.WindUp, 1)
+		}
+		x, y := float32(telegraph.Position[0]), float32(telegraph.Position[1])
+
+		switch attack.Shape {
+		case BoxTelegraph:
+			width, height := float32(attack.Width), float32(attack.Height)
+			rendering.DrawColoredRect(screen, x-width/2, y-height/2, width, height, telegraphAreaColor)
+			rendering.DrawBox(screen, x-width*float32(progress)/2, y-height*float32(progress)/2, width*float32(progress), height*float32(progress))
+		default:
+			rendering.DrawColoredCircle(screen, x, y, float32(attack.Radius), telegraphAreaColor)
+			rendering.DrawCircle(screen, x, y, float32(attack.Radius*progress))
+		}
+	}
+}
+
+var telegraphAreaColor = color.RGBA{0xA0, 0x00, 0x00, 0x50}
+
+// actorCenter returns the center of the actor's bounding rectangle.
+func actorCenter(gameActor *actor.Actor) (float64, float64) {
+	rect := gameActor.GetBoundingRect()
+	return rect.PositionX + rect.Width/2, rect.PositionY + rect.Height/2
+}
@@ -0,0 +1,86 @@
+package gameplay
+
+import (
+	"github.com/actor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+	"github.com/rendering"
+)
+
+// DialogChoice is an answer the player can pick in a choice dialog.
+type DialogChoice struct {
+	Text   string
+	Action input.Action // Shortcut that picks the choice right away
+}
+
+// ChoiceDialog is a modal dialog above the speaker that asks the player to pick one of a few choices.
+type ChoiceDialog struct {
+	UI       *rendering.UI
+	Choices  []DialogChoice
+	name     *rendering.Label
+	portrait *rendering.Picture
+	question *rendering.Label
+	chosen   int
+}
+
+// NoChoice is returned by ChoiceDialog.Update while the player hasn't picked anything.
+const NoChoice = -1
+
+// NewChoiceDialog creates a dialog with a button for each choice.
+func NewChoiceDialog(choices ...DialogChoice) *ChoiceDialog {
+	dialog := &ChoiceDialog{
+		Choices:  choices,
+		name:     &rendering.Label{Centered: true},
+		portrait: &rendering.Picture{Width: 64, Height: 64},
+		question: &rendering.Label{Centered: true},
+		chosen:   NoChoice,
+	}
+
+	buttons := &rendering.Panel{Spacing: 4}
+	for i, choice := range choices {
+		buttons.Children = append(buttons.Children, &rendering.Button{
+			Text:    choice.Text,
+			OnClick: func() { dialog.chosen = i },
+		})
+	}
+
+	panel := rendering.NewPanel(dialog.name, dialog.portrait, dialog.question, buttons)
+	panel.MinWidth = 260
+	dialog.UI = rendering.NewUI(panel)
+	dialog.UI.Anchor = rendering.AnchorPoint
+	dialog.UI.Offset = [2]float64{0, 8}
+	dialog.UI.Modal = true
+	return dialog
+}
+
+// Ask opens the dialog above the speaker with the question, and puts the focus on the first choice.
+func (dialog *ChoiceDialog) Ask(speaker *actor.Actor, question string) {
+	rect := speaker.GetBoundingRect()
+	dialog.UI.Point = [2]float64{rect.PositionX + rect.Width/2, rect.PositionY}
+	dialog.name.Text = speaker.Name
+	dialog.portrait.Image = speaker.Image
+	dialog.question.Text = question
+	dialog.chosen = NoChoice
+	dialog.UI.FocusFirst()
+}
+
+// Update handles the input and returns the index of the picked choice, or NoChoice.
+func (dialog *ChoiceDialog) Update(controls *input.Mapper) int {
+	for i, choice := range dialog.Choices {
+		if controls != nil && controls.JustPressed(choice.Action) {
+			dialog.chosen = i
+		}
+	}
+	if dialog.chosen == NoChoice {
+		dialog.UI.Update()
+	}
+
+	chosen := dialog.chosen
+	dialog.chosen = NoChoice
+	return chosen
+}
+
+// Draw draws the dialog.
+func (dialog *ChoiceDialog) Draw(screen *ebiten.Image) {
+	dialog.UI.Draw(screen)
+}
@@ -0,0 +1,95 @@
+package gameplay
+
+import (
+	"github.com/actor"
+	"github.com/events"
+	"github.com/player"
+)
+
+// The events the game modes publish to the game's event bus, see Context.Events.
+
+// NPCPurged is published when the player purges an NPC.
+type NPCPurged struct {
+	NPC    *actor.Actor
+	Purged int // Number of NPCs purged in the game so far, this one included
+}
+
+// NPCSpared is published when the player spares an NPC.
+type NPCSpared struct {
+	NPC    *actor.Actor
+	Spared int // Number of NPCs spared in the game so far, this one included
+}
+
+// AbilityCast is published when the player casts an ability.
+type AbilityCast struct {
+	Ability player.AbilityType
+	Player  *player.Player
+}
+
+// PlayerDamaged is published when the player takes damage.
+type PlayerDamaged struct {
+	Amount int
+	Health int // Health left after the hit
+}
+
+// LevelUp is published when the player reaches a new level.
+type LevelUp struct {
+	Level     int
+	MaxHealth int // Maximum health at the new level
+	MaxMana   int // Maximum mana at the new level
+}
+
+// BossAttackLanded is published when a boss attack lands.
+type BossAttackLanded struct {
+	Boss   *Boss
+	Attack *BossAttack
+	Hit    bool // The player was inside the area of the attack
+}
+
+// BossDefeated is published when a boss's health runs out and it starts dying.
+type BossDefeated struct {
+	Boss *Boss
+}
+
+// ActorRemoved is published when the entity manager takes an actor out of the game.
+type ActorRemoved struct {
+	Actor *actor.Actor
+}
+
+// StateChanged is published when the game status changes.
+type StateChanged struct {
+	From int
+	To   int
+}
+
+// publishPlayerEvents publishes the casts, the hits and the level-ups of the player to the bus.
+func publishPlayerEvents(bus *events.Bus, hero *player.Player) {
+	hero.OnCast = func(abilityType player.AbilityType) {
+		events.Publish(bus, AbilityCast{Ability: abilityType, Player: hero})
+	}
+	hero.OnDamaged = func(amount int) {
+		events.Publish(bus, PlayerDamaged{Amount: amount, Health: hero.Health})
+	}
+	hero.OnLevelUp = func(level int) {
+		events.Publish(bus, LevelUp{Level: level, MaxHealth: hero.MaxHealth, MaxMana: hero.MaxMana})
+	}
+}
+
+// publishRemovals publishes the actors the entity manager removes to the bus.
+func publishRemovals(bus *events.Bus, entities *actor.Manager) {
+	entities.OnRemove = func(removed *actor.Actor) {
+		events.Publish(bus, ActorRemoved{Actor: removed})
+	}
+}
+
+// publishBossEvents publishes the landed attacks and the defeats of the bosses to the bus.
+func publishBossEvents(bus *events.Bus, bosses []*Boss) {
+	for _, boss := range bosses {
+		boss.OnLand = func(attack *BossAttack, hit bool) {
+			events.Publish(bus, BossAttackLanded{Boss: boss, Attack: attack, Hit: hit})
+		}
+		boss.OnDefeat = func() {
+			events.Publish(bus, BossDefeated{Boss: boss})
+		}
+	}
+}
@@ -10,94 +10,112 @@ import (
 
 	"github.com/rendering" // Replace with the correct path to the rendering package
 
+	"github.com/input"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type ModeFrostmourneHungers struct {
 	BasePlayMode
 }
 
-// These functions need to be clalled each game tick
-func (playmode *ModeFrostmourneHungers) Tick(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
-	playmode.InitActors(gameActors)
-	playmode.PurgeIfInAoE(gameState, gameActors, player)
-	playmode.HandlePlayerInput(gameState, gameActors, player.Actor)
-	playmode.CheckGameOverAndUpdateState(gameState, gameActors, player)
-}
-
-func (playmode *ModeFrostmourneHungers) EncounterNPCs(gameState *GameState, npc *actor.Actor) {
-	// If the player is witin range/in collision with the NPC
-	// Enable an action PURGE! button that the player can press
-
-	// If the player's AoE ability is active, the player can purge all NPCs in range
-}
-
-func (playmode *ModeFrostmourneHungers) Purge(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
-	gameState.PurgedCount += 1
-	playmode.RemoveNPC(gameState, gameActors, npcActor)
+func init() {
+	RegisterMode(ModeInfo{
+		ID:          2,
+		Name:        "Frostmourne Hungers",
+		Description: "Sate Frostmourne with every living soul in Stratholme\nbefore the time runs out.",
+		Preview:     "./assets/dk.png",
+		WinText:     "Frostmourne is sated!",
+		LossText:    "Frostmourne hungers still - Stratholme holds out.",
+		New:         func() PlayMode { return &ModeFrostmourneHungers{} },
+	})
 }
 
 func (playmode *ModeFrostmourneHungers) PurgeIfInAoE(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
-	for _, npcActor := range gameActors {
-		if !npcActor.Draw || !npcActor.CollisionEnabled || len((player.Abilities)) == 0 {
-			continue
-		}
-		if !npcActor.CollidesWithAbility(player.Abilities[0].Actor) {
+	for _, ability := range player.Abilities {
+		if ability.Type != deathAndDecayType {
 			continue
 		}
-		// If the NPC is in the player's AoE ability, purge it
-		playmode.Purge(gameState, gameActors, npcActor)
-		if gameState.PurgedCount%4 == 0 {
-			player.LevelUp()
+		for _, npcActor := range gameActors {
+			if !npcActor.Draw || !npcActor.CollisionEnabled || !npcActor.CollidesWithAbility(ability.Actor) {
+				continue
+			}
+			// If the NPC is in the player's AoE ability, purge it
+			playmode.Purge(gameState, gameActors, npcActor)
 		}
 	}
 }
 
-func (playmode *ModeFrostmourneHungers) HandleKeyboardInput(
+func (playmode *ModeFrostmourneHungers) HandleInput(
 	gameState *GameState,
 	player *player.Player,
 	gameActors []*actor.Actor,
-	keys []ebiten.Key) {
-	player.HandleInput(keys)
+	controls *input.Mapper) {
+	player.HandleInput(controls.Movement())
 	// What if the NPC goes over the player?
 
 	playmode.PurgeIfInAoE(gameState, gameActors, player)
 
-	if inpututil.IsKeyJustPressed(ebiten.KeyD) {
+	if controls.JustPressed(input.CastAoE) {
 		player.DeathAndDecay()
 	}
-}
-func (playmode *ModeFrostmourneHungers) HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor) {
-	// Might not be needed as this mode does not have "Waiting" as game state
-}
-
-func (playmode *ModeFrostmourneHungers) CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
-	if len(gameActors) == 0 {
-		gameState.Status = StatusMap[GameWon]
-	} else if player.Health <= 0 {
-		gameState.Status = StatusMap[GameLost]
+	if controls.JustPressed(input.CastDeathCoil) {
+		player.DeathCoil()
 	}
 }
-
 func (playmode *ModeFrostmourneHungers) InitPlayer() *player.Player {
 	// Initialize the player actor
 	playerTexture := rendering.CreateTexture(utils.LoadFile("./assets/dk.png"))
 	playerActor := actor.NewActor([2]float64{0, 0}, playerTexture, 14, "Purger", true)
-	return player.NewPlayer(playerActor)
+	Animate(playerActor, DefaultClips(playerTexture))
+	deathKnight := player.NewPlayer(playerActor)
+	deathKnight.AbilitySlots = append(deathKnight.AbilitySlots, player.DeathCoilSlot())
+	deathKnight.TalentTree = talentTree(player.DeathKnightTalents)
+	return deathKnight
+}
+
+// InitNPCs sets up the endless wave spawner and spawns the first wave.
+func (playmode *ModeFrostmourneHungers) InitNPCs() {
+	playmode.Spawner = &Spawner{
+		SpawnPoints: DefaultSpawnPoints(),
+		Archetypes: []*Archetype{
+			{Name: "Citizen", TexturePath: "./assets/scv.png", Speed: 1, XP: 10},
+			{Name: "Ghoul", TexturePath: "./assets/scourge.png", Speed: 2, XP: 15},
+			{Name: "Abomination", TexturePath: "./assets/pudge.PNG", Speed: 4, XP: 30},
+		},
+		WaveInterval: 15,
+		BaseCount:    7,
+		CountGrowth:  3,
+	}
+	playmode.WinCondition = WinCondition{Type: PurgeWithinTime, PurgeTarget: 60, TimeLimit: 120}
+
+	playmode.Spawner.Update(&GameState{}, playmode.Entities)
 }
 
-func (playmode *ModeFrostmourneHungers) InitNPCs() []*actor.Actor {
-	// Initialize the NPC actors
-	scourgeTexture := rendering.CreateTexture(utils.LoadFile("./assets/scv.png"))
+// Controls adds Death and Decay to the shared controls.
+func (playmode *ModeFrostmourneHungers) Controls() []ControlHint {
+	return append(playmode.BasePlayMode.Controls(),
+		ControlHint{Actions: []input.Action{input.CastAoE}, Description: "Death and Decay"},
+		ControlHint{Actions: []input.Action{input.CastDeathCoil}, Description: "Death Coil the target"},
+	)
+}
 
-	npcActor := actor.NewActor([2]float64{200, 200}, scourgeTexture, 4, "Scourge", true)
-	npcActor1 := actor.NewActor([2]float64{400, 200}, scourgeTexture, 1, "Undead1", true)
-	npcActor2 := actor.NewActor([2]float64{500, 300}, scourgeTexture, 1, "Undead2", true)
-	npcActor3 := actor.NewActor([2]float64{250, 50}, scourgeTexture, 1, "Undead3", true)
-	npcActor4 := actor.NewActor([2]float64{350, 50}, scourgeTexture, 1, "Undead3", true)
-	npcActor5 := actor.NewActor([2]float64{450, 70}, scourgeTexture, 1, "Undead3", true)
-	npcActor6 := actor.NewActor([2]float64{200, 600}, scourgeTexture, 1, "Undead3", true)
+// Enter sends the death knight into the night of Stratholme.
+func (playmode *ModeFrostmourneHungers) Enter(ctx *Context) {
+	playmode.attach(ctx)
+	ctx.Player = playmode.InitPlayer()
+	playmode.InitNPCs()
+	playmode.enter(ctx, playmode.Controls())
+	ctx.Lighting = stratholmeLighting(ctx.Player)
+}
+
+// Update runs a tick of the timed purge.
+func (playmode *ModeFrostmourneHungers) Update(ctx *Context) {
+	playmode.tick(ctx, playmode)
+}
 
-	return []*actor.Actor{npcActor, npcActor1, npcActor2, npcActor3, npcActor4, npcActor5, npcActor6}
+// Draw draws Stratholme at night.
+func (playmode *ModeFrostmourneHungers) Draw(screen *ebiten.Image, ctx *Context) {
+	drawStratholme(screen)
+	playmode.BasePlayMode.Draw(screen, ctx)
 }
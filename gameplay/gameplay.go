@@ -4,19 +4,21 @@ import (
 	_ "image/png"
 
 	"github.com/actor"
+	"github.com/events"
 
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+	"github.com/logging"
 	"github.com/player"
-	"github.com/rendering"
 )
 
-type Hud struct {
-	PlayerControls  []*ebiten.Image // help overlay for player controls
-	PlayerAbilities []*ebiten.Image // buttons for player abilities (with bindings)
-	PlayerStats     []*ebiten.Image // player stats (health, mana, etc.)
-	PlayerNameplate *ebiten.Image   // player nameplate
-	GameMenu        []*ebiten.Image // game menu images (start, pause, etc.)
-	NPCNameplate    *ebiten.Image   // NPC nameplate
+var logger = logging.For(logging.Gameplay)
+
+// ControlHint is a line of the controls overlay - the actions and what they do.
+type ControlHint struct {
+	Actions     []input.Action
+	Input       string // Input that can't be rebound, like a mouse button, shown when there are no actions
+	Description string
 }
 
 type GameStatus string
@@ -28,83 +30,217 @@ type GameState struct {
 	PurgedCount      int
 	SparedCount      int
 	Target           *actor.Actor
-	TimeElapsed      float64
+	TimeElapsed      float64 // Seconds of active (not paused) play
+	Delta            float64 // Seconds of the current tick, for the rules that go by the second
+	TimeLeft         float64 // Seconds left to meet a timed win condition
+	Wave             int     // Number of the last spawned wave
+	WaveCount        int     // Total number of waves, 0 if the waves never stop
+	NextWaveIn       float64 // Seconds until the next wave
 	Won              bool
 	Lost             bool
 }
 
-type PlayMode interface {
-	EncounterNPCs(gameState *GameState, npc *actor.Actor)
-	InitActors(npcActors []*actor.Actor)
-	PauseGame(gameState *GameState, screen *ebiten.Image, ScreenWidth, ScreenHeight float64)
-	PropmptPlayer(gameState *GameState, player *actor.Actor, screen *ebiten.Image)
-	Purge(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor)
-	HandleKeyboardInput(gameState *GameState, player *player.Player, gameActors []*actor.Actor, keys []ebiten.Key)
-	HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor)
-	EndGame(gameState *GameState, screen *ebiten.Image)
-	CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player)
-	InitPlayer() *player.Player
-	InitNPCs() []*actor.Actor
-	RemoveNPC(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor)
-}
+type WinConditionType string
 
-type BasePlayMode struct{}
+const (
+	SurviveWaves    WinConditionType = "SurviveWaves"    // Clear all NPCs of the last wave
+	PurgeWithinTime WinConditionType = "PurgeWithinTime" // Purge a number of NPCs before the time runs out
+	DefeatBoss      WinConditionType = "DefeatBoss"      // Defeat all bosses of the mode
+	EscortToExit    WinConditionType = "EscortToExit"    // Bring an escorted actor to the exit, the mode marks the game as won
+)
 
-// EndGame is called when the game is over.
-// It displays a message indicating that the game has ended and the player has completed the game.
-func (b *BasePlayMode) EndGame(gameState *GameState, screen *ebiten.Image) {
-	choiceText := "Congratulations! You have completed the game!"
-	rendering.DrawBox(screen, float32(screen.Bounds().Dx()/2-200), float32(screen.Bounds().Dy()/2-50), 400, 100)
-	rendering.DrawCenteredText(screen, choiceText, float64(screen.Bounds().Dx()/2), float64(screen.Bounds().Dy()/2))
-	gameState.Status = StatusMap[GameEnded]
+// WinCondition describes what the player needs to do to win a game mode.
+type WinCondition struct {
+	Type        WinConditionType
+	PurgeTarget int     // Number of NPCs to purge, used by PurgeWithinTime
+	TimeLimit   float64 // Time limit in seconds, used by PurgeWithinTime
 }
 
-// It draws the player prompt at the actor's position on the screen.
-func (playmode *BasePlayMode) PauseGame(gameState *GameState, screen *ebiten.Image, ScreenWidth, ScreenHeight float64) {
-	choiceText := "Game Paused"
-	rendering.DrawBox(screen, float32(ScreenWidth/2-100), float32(ScreenHeight/2-50), 200, 100)
-	rendering.DrawCenteredText(screen, choiceText, ScreenWidth/2, ScreenHeight/2)
+const deathCoilDamage = 10
+
+// Aliases of the ability types, the player package is shadowed in the mode methods
+const (
+	deathAndDecayType = player.DeathAndDecayType
+	deathCoilType     = player.DeathCoilType
+	burstOfLightType  = player.BurstOfLightType
+)
+
+type BasePlayMode struct {
+	Entities     *actor.Manager // Actors of the game, set when the mode enters
+	Events       *events.Bus    // Where the mode publishes what happens in the game, set when the mode enters
+	Spawner      *Spawner
+	Summons      []*Archetype // Kinds of NPCs the mode calls in outside of the waves, e.g. by a boss
+	WinCondition WinCondition
+	Bosses       []*Boss
+	Prompt       *ChoiceDialog // Asks the player to choose when the game is awaiting them, optional
 }
 
+// InitActors makes the NPCs patrol around their spawn point.
 func (playmode *BasePlayMode) InitActors(npcActors []*actor.Actor) {
 	for npcActor := range npcActors {
-		if !npcActors[npcActor].Draw {
+		if !npcActors[npcActor].Present() {
 			continue
 		}
 		npcActors[npcActor].Patrol(10)
 	}
 }
 
-// It draws the player prompt at the actor's position on the screen.
-func (playmode *BasePlayMode) PropmptPlayer(gameState *GameState, player *actor.Actor, screen *ebiten.Image) {
-	rendering.DrawPlayerPromptAtActorPos(screen, gameState.PromptPlayerText, player.Position)
-}
-
 // RemoveActor is called to remove an actor from the game.
-// It sets the actor's Draw property to false, effectively removing it from the game.
-// TODO: optimize by either removing the actor from the slice or using a pool of actors
+// The actor plays its death clip, and the tick removes it once the clip ends.
 func (playmde *BasePlayMode) RemoveActor(gameActors []*actor.Actor, npcActor *actor.Actor) {
-	npcActor.Draw = false
+	npcActor.Die()
 }
 
-// removeNPC is called to remove an NPC from the game.
-// It removes the actor from the game, sets the PromptPlayer to false,
-// and checks if the game is over.
+// RemoveNPC is called to remove an NPC from the game.
 func (playmode *BasePlayMode) RemoveNPC(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
 	playmode.RemoveActor(gameActors, npcActor)
-	gameState.PromptPlayer = false
-	gameState.Status = StatusMap[GameStarted]
-}
-
-// Game mode factory
-// This function creates a new PlayMode instance based on the provided gameMode parameter.
-func NewPlayMode(gameMode int) PlayMode {
-	switch gameMode {
-	case 1:
-		return &ModeInvincible{}
-	case 2:
-		return &ModeFrostmourneHungers{}
-	default:
+}
+
+// Purge counts the purged NPC, removes it from the game and publishes NPCPurged.
+func (playmode *BasePlayMode) Purge(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
+	gameState.PurgedCount += 1
+	playmode.RemoveNPC(gameState, gameActors, npcActor)
+	events.Publish(playmode.Events, NPCPurged{NPC: npcActor, Purged: gameState.PurgedCount})
+}
+
+// Spare counts the spared NPC, fades it out of the game and publishes NPCSpared.
+func (playmode *BasePlayMode) Spare(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
+	gameState.SparedCount += 1
+	npcActor.Leave()
+	events.Publish(playmode.Events, NPCSpared{NPC: npcActor, Spared: gameState.SparedCount})
+}
+
+// SpawnNPCs is called each game tick and spawns the NPCs of the next wave once it is due.
+func (playmode *BasePlayMode) SpawnNPCs(gameState *GameState) {
+	if playmode.Spawner != nil {
+		playmode.Spawner.Update(gameState, playmode.Entities)
+	}
+}
+
+// UpdateWorld is called each game tick to update the mode's own world, by default the bosses.
+func (playmode *BasePlayMode) UpdateWorld(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	playmode.UpdateBosses(gameState, player)
+	playmode.LandProjectiles(gameState, gameActors, player)
+}
+
+// Draw draws the mode's map and world effects below the actors, by default the boss attacks.
+func (playmode *BasePlayMode) Draw(screen *ebiten.Image, ctx *Context) {
+	playmode.DrawBossTelegraphs(ctx.State, screen)
+}
+
+// LandProjectiles moves the player's projectiles and applies the ones that hit their target.
+func (playmode *BasePlayMode) LandProjectiles(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	for _, ability := range player.UpdateProjectiles() {
+		player.Bounce(ability, gameActors)
+		boss := playmode.bossOf(ability.Target)
+		switch {
+		case ability.Type == deathCoilType && boss != nil:
+			boss.TakeDamage(deathCoilDamage * player.DamageMultiplier())
+		case ability.Type == deathCoilType:
+			playmode.Purge(gameState, gameActors, ability.Target)
+		case ability.Type == burstOfLightType && boss == nil:
+			playmode.Spare(gameState, gameActors, ability.Target)
+		}
+	}
+}
+
+// talentTrees are the loaded talent trees, by data file.
+var talentTrees = map[string]*player.TalentTree{}
+
+// talentTree loads the talent tree from its data file, or returns nil if it can't be loaded.
+func talentTree(path string) *player.TalentTree {
+	if tree, ok := talentTrees[path]; ok {
+		return tree
+	}
+	tree, err := player.LoadTalentTree(path)
+	if err != nil {
+		logger.Error("loading the talents", "path", path, "err", err)
 		return nil
 	}
+	talentTrees[path] = tree
+	return tree
+}
+
+// bossOf returns the boss driving the actor, or nil if the actor isn't a boss.
+func (playmode *BasePlayMode) bossOf(gameActor *actor.Actor) *Boss {
+	for _, boss := range playmode.Bosses {
+		if boss.Actor == gameActor {
+			return boss
+		}
+	}
+	return nil
+}
+
+// FindPath returns the waypoints to a point picked with the mouse, by default the point itself.
+func (playmode *BasePlayMode) FindPath(from, to [2]float64) [][2]float64 {
+	return [][2]float64{to}
+}
+
+// UpdateBosses runs the phases and the attack patterns of all bosses.
+func (playmode *BasePlayMode) UpdateBosses(gameState *GameState, player *player.Player) {
+	for _, boss := range playmode.Bosses {
+		boss.Update(gameState, player)
+	}
+}
+
+// DrawBossTelegraphs draws the areas of the boss attacks that are about to land.
+func (playmode *BasePlayMode) DrawBossTelegraphs(gameState *GameState, screen *ebiten.Image) {
+	for _, boss := range playmode.Bosses {
+		boss.DrawTelegraphs(gameState, screen)
+	}
+}
+
+// Controls returns the controls shared by all modes, for the controls overlay.
+func (playmode *BasePlayMode) Controls() []ControlHint {
+	return []ControlHint{
+		{Actions: []input.Action{input.MoveUp, input.MoveLeft, input.MoveDown, input.MoveRight}, Description: "Move"},
+		{Actions: []input.Action{input.Pause}, Description: "Pause"},
+		{Actions: []input.Action{input.ToggleControls}, Description: "Show/hide controls"},
+		{Input: "Right click", Description: "Walk to the point"},
+		{Input: "Left click", Description: "Target the NPC"},
+		{Actions: []input.Action{input.CycleTarget}, Description: "Target the next NPC, nearest first"},
+	}
+}
+
+// CheckWinCondition checks the mode's win condition and updates the game state.
+func (playmode *BasePlayMode) CheckWinCondition(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	switch playmode.WinCondition.Type {
+	case SurviveWaves:
+		if (playmode.Spawner == nil || playmode.Spawner.Done()) && len(gameActors) == 0 {
+			gameState.Won = true
+		}
+	case DefeatBoss:
+		gameState.Won = len(playmode.Bosses) > 0
+		for _, boss := range playmode.Bosses {
+			if !boss.Defeated() {
+				gameState.Won = false
+			}
+		}
+	case PurgeWithinTime:
+		gameState.TimeLeft = max(playmode.WinCondition.TimeLimit-gameState.TimeElapsed, 0)
+		if gameState.PurgedCount >= playmode.WinCondition.PurgeTarget {
+			gameState.Won = true
+		} else if gameState.TimeLeft <= 0 {
+			gameState.Lost = true
+		}
+	}
+
+	if player.Health <= 0 {
+		gameState.Lost = true
+	}
+
+	if gameState.Lost {
+		gameState.Status = StatusMap[GameLost]
+	} else if gameState.Won {
+		gameState.Status = StatusMap[GameWon]
+	}
+}
+
+// CheckGameOverAndUpdateState ends the game once the mode's win condition is met or the player is dead.
+func (playmode *BasePlayMode) CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	playmode.CheckWinCondition(gameState, gameActors, player)
+}
+
+// HandlePlayerInput handles the player's answer while the game awaits them.
+func (playmode *BasePlayMode) HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor, controls *input.Mapper) {
 }
@@ -10,7 +10,10 @@ replace github.com/game => ../game
 
 require (
 	github.com/actor v0.0.0-00010101000000-000000000000
+	github.com/events v0.0.0-00010101000000-000000000000
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
+	github.com/input v0.0.0-00010101000000-000000000000
+	github.com/logging v0.0.0-00010101000000-000000000000
 	github.com/player v0.0.0-00010101000000-000000000000
 	github.com/rendering v0.0.0-00010101000000-000000000000
 	github.com/utils v0.0.0-00010101000000-000000000000
@@ -32,3 +35,9 @@ require (
 replace github.com/utils => ../utils
 
 replace github.com/player => ../player
+
+replace github.com/input => ../input
+
+replace github.com/logging => ../logging
+
+replace github.com/events => ../events
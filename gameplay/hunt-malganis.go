@@ -0,0 +1,316 @@
+package gameplay
+
+import (
+	"image/color"
+	_ "image/png"
+	"math"
+	"strconv"
+
+	"github.com/actor"
+	"github.com/player"
+
+	"github.com/utils"
+
+	"github.com/rendering"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+)
+
+// Anub'Arak's phases
+const (
+	AnubArakPhaseCarapace = "Carapace" // Chases the player, impales and pounds
+	AnubArakPhaseBurrow   = "Burrow"   // Burrows underground, calls a swarm of Nerubians and emerges somewhere else
+	AnubArakPhaseEnraged  = "Enraged"  // Enrages - faster, with the Leeching Swarm and more blizzards
+
+	anubArakMaxHealth      = 1000.0
+	anubArakBurrowDuration = 6.0 // Seconds Anub'Arak stays underground
+	anubArakSwarmSize      = 6
+
+	blizzardInterval      = 4.0  // Seconds between two blizzards
+	blizzardDuration      = 8.0  // Seconds a blizzard lasts
+	blizzardRadius        = 70.0 // Radius of the blizzard AoE
+	blizzardSlow          = 0.5  // Multiplier of the player's speed inside a blizzard
+	contactDamage         = 5    // Damage from touching a Nerubian
+	contactDamageCooldown = 1.0  // Seconds between two contact hits
+	deathAndDecayDamage   = 60.0 // Damage per second to the boss inside Death and Decay, at level 1
+)
+
+// Anub'Arak's attacks
+var (
+	impale = &BossAttack{
+		Name: "Impale", Shape: CircleTelegraph, Target: AtPlayer,
+		Radius: 50, WindUp: 1.5, Cooldown: 3, Damage: 15, Shake: 3,
+	}
+	pound = &BossAttack{
+		Name: "Pound", Shape: BoxTelegraph, Target: AtBoss,
+		Width: 260, Height: 60, WindUp: 1.2, Cooldown: 2.5, Damage: 20, Shake: 8,
+	}
+	leechingSwarm = &BossAttack{
+		Name: "Leeching Swarm", Shape: CircleTelegraph, Target: AtBoss,
+		Radius: 150, WindUp: 2, Cooldown: 4, Damage: 10,
+	}
+	demolish = &BossAttack{
+		Name: "Demolish", Shape: CircleTelegraph, Target: AtBoss,
+		Radius: 220, WindUp: 2.5, Cooldown: 5, Damage: 30, Shake: 14,
+	}
+)
+
+// Blizzard is a weather AoE that slows the player while they are inside it.
+type Blizzard struct {
+	Position  [2]float64 // Center of the blizzard
+	Radius    float64
+	ExpiresAt float64 // Game time in seconds at which the blizzard ends
+}
+
+// Contains reports whether the actor's center is inside the blizzard.
+func (blizzard *Blizzard) Contains(gameActor *actor.Actor) bool {
+	rect := gameActor.GetBoundingRect()
+	dx := rect.PositionX + rect.Width/2 - blizzard.Position[0]
+	dy := rect.PositionY + rect.Height/2 - blizzard.Position[1]
+	return dx*dx+dy*dy <= blizzard.Radius*blizzard.Radius
+}
+
+// ModeHuntMalGanis follows Mal'Ganis to Northrend, where Anub'Arak waits.
+type ModeHuntMalGanis struct {
+	BasePlayMode
+	Boss          *Boss // Anub'Arak
+	Blizzards     []*Blizzard
+	nextBlizzard  float64      // Game time in seconds at which the next blizzard starts
+	lastHitAt     float64      // Game time in seconds at which the player was last hit
+	burrowedUntil float64      // Game time in seconds at which Anub'Arak emerges, 0 if he is not burrowed
+	snowdrifts    [][3]float32 // x, y and radius of the snowdrifts on the map
+	nerubianSwarm *Archetype
+}
+
+func init() {
+	RegisterMode(ModeInfo{
+		ID:          3,
+		Name:        "Hunt Mal'Ganis",
+		Description: "Follow Mal'Ganis to frozen Northrend, through the blizzards\nand the Nerubian swarms, and defeat Anub'Arak.",
+		Preview:     "./assets/pudge.PNG",
+		WinText:     "Anub'Arak is defeated, and Mal'Ganis has nowhere left to run!",
+		LossText:    "Northrend has claimed another prince.",
+		New:         func() PlayMode { return &ModeHuntMalGanis{} },
+	})
+}
+
+// Purge removes a Nerubian from the game. Anub'Arak has to be defeated instead.
+func (playmode *ModeHuntMalGanis) Purge(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
+	if npcActor == playmode.Boss.Actor {
+		return
+	}
+	playmode.BasePlayMode.Purge(gameState, gameActors, npcActor)
+}
+
+func (playmode *ModeHuntMalGanis) HandleInput(
+	gameState *GameState,
+	player *player.Player,
+	gameActors []*actor.Actor,
+	controls *input.Mapper) {
+	player.HandleInput(controls.Movement())
+
+	if controls.JustPressed(input.CastAoE) {
+		player.DeathAndDecay()
+	}
+	if controls.JustPressed(input.CastDeathCoil) {
+		player.DeathCoil()
+	}
+}
+
+// InitActors makes the Nerubians patrol, while Anub'Arak is moved by UpdateWorld.
+func (playmode *ModeHuntMalGanis) InitActors(npcActors []*actor.Actor) {
+	for _, npcActor := range npcActors {
+		if !npcActor.Present() || npcActor == playmode.Boss.Actor {
+			continue
+		}
+		npcActor.Patrol(10)
+	}
+}
+
+// UpdateWorld runs the blizzards, the boss encounter and the damage exchange each game tick.
+func (playmode *ModeHuntMalGanis) UpdateWorld(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
+	playmode.updateBlizzards(gameState, player)
+	playmode.emergeIfDue(gameState)
+	playmode.UpdateBosses(gameState, player)
+	playmode.LandProjectiles(gameState, gameActors, player)
+
+	for _, ability := range player.Abilities {
+		if ability.Type != deathAndDecayType {
+			continue
+		}
+		for _, npcActor := range gameActors {
+			if !npcActor.Draw || !npcActor.CollisionEnabled || !npcActor.CollidesWithAbility(ability.Actor) {
+				continue
+			}
+			if npcActor == playmode.Boss.Actor {
+				playmode.Boss.TakeDamage(deathAndDecayDamage * gameState.Delta * player.DamageMultiplier())
+				continue
+			}
+			playmode.Purge(gameState, gameActors, npcActor)
+		}
+	}
+
+	if gameState.TimeElapsed-playmode.lastHitAt < contactDamageCooldown {
+		return
+	}
+	for _, npcActor := range gameActors {
+		if !npcActor.Draw || !npcActor.CollisionEnabled || !player.Actor.CollidesWith(npcActor) {
+			continue
+		}
+		damage := contactDamage
+		if npcActor == playmode.Boss.Actor {
+			damage = contactDamage * (2 + playmode.Boss.PhaseIndex)
+		}
+		player.TakeDamage(damage)
+		playmode.lastHitAt = gameState.TimeElapsed
+		return
+	}
+}
+
+// updateBlizzards starts and expires the blizzards, and slows the player down inside them.
+func (playmode *ModeHuntMalGanis) updateBlizzards(gameState *GameState, player *player.Player) {
+	if gameState.TimeElapsed >= playmode.nextBlizzard {
+		interval := blizzardInterval
+		if playmode.Boss.CurrentPhase().Name == AnubArakPhaseEnraged {
+			interval /= 2
+		}
+		playmode.nextBlizzard = gameState.TimeElapsed + interval
+		playmode.Blizzards = append(playmode.Blizzards, &Blizzard{
+			Position: [2]float64{
+				utils.GetRandomNumInRange(blizzardRadius, rendering.ScreenWidth-blizzardRadius),
+				utils.GetRandomNumInRange(blizzardRadius, rendering.ScreenHeight-blizzardRadius),
+			},
+			Radius:    blizzardRadius,
+			ExpiresAt: gameState.TimeElapsed + blizzardDuration,
+		})
+	}
+
+	activeBlizzards := make([]*Blizzard, 0, len(playmode.Blizzards))
+	player.Actor.Speed = player.MoveSpeed()
+	for _, blizzard := range playmode.Blizzards {
+		if blizzard.ExpiresAt <= gameState.TimeElapsed {
+			continue
+		}
+		if blizzard.Contains(player.Actor) {
+			player.Actor.Speed = player.MoveSpeed() * blizzardSlow
+		}
+		activeBlizzards = append(activeBlizzards, blizzard)
+	}
+	playmode.Blizzards = activeBlizzards
+}
+
+// burrow hides Anub'Arak underground and calls a swarm of Nerubians.
+func (playmode *ModeHuntMalGanis) burrow(boss *Boss, gameState *GameState) {
+	playmode.burrowedUntil = gameState.TimeElapsed + anubArakBurrowDuration
+	boss.Actor.Draw = false
+	for range anubArakSwarmSize {
+		playmode.nerubianSwarm.NewNPC(playmode.Entities, boss.Actor.Position)
+	}
+}
+
+// emergeIfDue brings Anub'Arak back at a random spot once his time underground is over.
+func (playmode *ModeHuntMalGanis) emergeIfDue(gameState *GameState) {
+	if playmode.burrowedUntil == 0 || gameState.TimeElapsed < playmode.burrowedUntil {
+		return
+	}
+
+	boss := playmode.Boss
+	bossRect := boss.Actor.GetBoundingRect()
+	boss.Actor.Position = [2]float64{
+		utils.GetRandomNumInRange(0, rendering.ScreenWidth-bossRect.Width),
+		utils.GetRandomNumInRange(0, rendering.ScreenHeight-bossRect.Height),
+	}
+	boss.Actor.Draw = true
+	playmode.burrowedUntil = 0
+}
+
+// Enter sends the death knight to Northrend, after Anub'Arak.
+func (playmode *ModeHuntMalGanis) Enter(ctx *Context) {
+	playmode.attach(ctx)
+	ctx.Player = playmode.InitPlayer()
+	playmode.InitNPCs()
+	playmode.enter(ctx, playmode.Controls())
+}
+
+// Update runs a tick of the hunt - the blizzards, Anub'Arak and his Nerubians.
+func (playmode *ModeHuntMalGanis) Update(ctx *Context) {
+	playmode.tick(ctx, playmode)
+}
+
+// Draw draws the frozen tundra of Northrend and the blizzards.
+func (playmode *ModeHuntMalGanis) Draw(screen *ebiten.Image, ctx *Context) {
+	screen.Fill(color.RGBA{0xC8, 0xD8, 0xE8, 0xFF})
+	for _, snowdrift := range playmode.snowdrifts {
+		rendering.DrawColoredCircle(screen, snowdrift[0], snowdrift[1], snowdrift[2], color.RGBA{0xF4, 0xF8, 0xFF, 0xFF})
+	}
+	for _, blizzard := range playmode.Blizzards {
+		rendering.DrawColoredCircle(screen, float32(blizzard.Position[0]), float32(blizzard.Position[1]), float32(blizzard.Radius), color.RGBA{0x60, 0x90, 0xC0, 0x60})
+	}
+
+	playmode.DrawBossTelegraphs(ctx.State, screen)
+}
+
+func (playmode *ModeHuntMalGanis) InitPlayer() *player.Player {
+	// Initialize the player actor
+	playerTexture := rendering.CreateTexture(utils.LoadFile("./assets/dk.png"))
+	playerActor := actor.NewActor([2]float64{0, 0}, playerTexture, 14, "Purger", true)
+	Animate(playerActor, DefaultClips(playerTexture))
+	deathKnight := player.NewPlayer(playerActor)
+	deathKnight.AbilitySlots = append(deathKnight.AbilitySlots, player.DeathCoilSlot())
+	deathKnight.TalentTree = talentTree(player.DeathKnightTalents)
+	return deathKnight
+}
+
+// InitNPCs places Anub'Arak, the Nerubian waves and the snowdrifts.
+func (playmode *ModeHuntMalGanis) InitNPCs() {
+	playmode.nerubianSwarm = &Archetype{Name: "Nerubian Swarmer", TexturePath: "./assets/scv.png", Speed: 3, XP: 5}
+	playmode.Summons = []*Archetype{playmode.nerubianSwarm}
+	playmode.Spawner = &Spawner{
+		SpawnPoints: DefaultSpawnPoints(),
+		Archetypes: []*Archetype{
+			{Name: "Nerubian Skitterer", TexturePath: "./assets/scv.png", Speed: 2, XP: 20},
+			{Name: "Nerubian Warrior", TexturePath: "./assets/scourge.png", Speed: 1, XP: 25},
+		},
+		WaveInterval: 20,
+		BaseCount:    3,
+		CountGrowth:  1,
+	}
+	playmode.WinCondition = WinCondition{Type: DefeatBoss}
+
+	bossTexture := rendering.CreateTexture(utils.LoadFile("./assets/pudge.PNG"))
+	bossActor := actor.NewActor([2]float64{rendering.ScreenWidth / 2, rendering.ScreenHeight / 2}, bossTexture, 1.5, "Anub'Arak", true)
+	Animate(bossActor, DefaultClips(bossTexture))
+	bossActor.Tag(actor.TagNPC, actor.TagBoss)
+	playmode.Entities.Add(bossActor)
+	playmode.Boss = NewBoss(bossActor, anubArakMaxHealth, []*BossPhase{
+		{Name: AnubArakPhaseCarapace, HealthThreshold: 1, Attacks: []*BossAttack{impale, pound}},
+		{Name: AnubArakPhaseBurrow, HealthThreshold: 0.6, Attacks: []*BossAttack{impale}, OnEnter: playmode.burrow},
+		{Name: AnubArakPhaseEnraged, HealthThreshold: 0.3, Speed: 3, Attacks: []*BossAttack{impale, leechingSwarm, pound, demolish}},
+	})
+	playmode.Bosses = []*Boss{playmode.Boss}
+
+	playmode.snowdrifts = make([][3]float32, 0, 12)
+	for range 12 {
+		playmode.snowdrifts = append(playmode.snowdrifts, [3]float32{
+			float32(utils.GetRandomNumInRange(0, rendering.ScreenWidth)),
+			float32(utils.GetRandomNumInRange(0, rendering.ScreenHeight)),
+			float32(utils.GetRandomNumInRange(20, 60)),
+		})
+	}
+
+	playmode.Spawner.Update(&GameState{}, playmode.Entities)
+}
+
+// formatHealth formats health as "current/max", rounding the current health up.
+func formatHealth(health, maxHealth float64) string {
+	return strconv.Itoa(int(math.Ceil(health))) + "/" + strconv.Itoa(int(maxHealth))
+}
+
+// Controls adds Death and Decay to the shared controls.
+func (playmode *ModeHuntMalGanis) Controls() []ControlHint {
+	return append(playmode.BasePlayMode.Controls(),
+		ControlHint{Actions: []input.Action{input.CastAoE}, Description: "Death and Decay"},
+		ControlHint{Actions: []input.Action{input.CastDeathCoil}, Description: "Death Coil the target"},
+	)
+}
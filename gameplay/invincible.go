@@ -10,14 +10,27 @@ import (
 
 	"github.com/rendering" // Replace with the correct path to the rendering package
 
+	"github.com/input"
+
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
 type ModeInvincible struct {
 	BasePlayMode
 }
 
+func init() {
+	RegisterMode(ModeInfo{
+		ID:          1,
+		Name:        "Invincible",
+		Description: "Meet the citizens of Stratholme one by one,\nand choose to purge or to spare each of them.",
+		Preview:     "./assets/arthas.png",
+		WinText:     "Every citizen of Stratholme has met their fate!",
+		LossText:    "The Scourge has overrun Stratholme!",
+		New:         func() PlayMode { return &ModeInvincible{} },
+	})
+}
+
 // Constants defining the allowed game states
 var (
 	GameMenu     GameStatus = "Menu"
@@ -47,59 +60,56 @@ func (playmode *ModeInvincible) EncounterNPCs(gameState *GameState, npc *actor.A
 	gameState.Target = npc
 	gameState.Status = StatusMap[AwaitingUser]
 	gameState.PromptPlayer = true
-	gameState.PromptPlayerText = "Press P to Purge or S to Spare"
-}
-
-// checkGameOverAndUpdateState checks if there are any remaining actors in the game.
-// If there are no actors left, it sets the game state to GameEnded.
-func (playmode *ModeInvincible) CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player) {
-	if len(gameActors) == 0 {
-		gameState.Status = StatusMap[GameEnded]
-	}
+	gameState.PromptPlayerText = "Purge or spare this citizen?"
+	playmode.Prompt.Ask(npc, gameState.PromptPlayerText)
 }
 
-// Purge is called when the player chooses to purge an NPC.
-// It increments the purged count and removes the NPC from the game.
-// It also checks if the game is over.
-func (playmode *ModeInvincible) Purge(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
-	gameState.PurgedCount += 1
-	playmode.RemoveNPC(gameState, gameActors, npcActor)
-}
-
-// Spare is called when the player chooses to spare an NPC.
-// It increments the spared count and removes the NPC from the game.
-// It also checks if the game is over.
-func (playmode *ModeInvincible) Spare(gameState *GameState, gameActors []*actor.Actor, npcActor *actor.Actor) {
-	gameState.SparedCount += 1
-	playmode.RemoveNPC(gameState, gameActors, npcActor)
-}
+// Indexes of the choices of the Purge or Spare dialog.
+const (
+	purgeChoice = iota
+	spareChoice
+)
 
 func (playmode *ModeInvincible) InitPlayer() *player.Player {
 	// Initialize the player actor
 	playerTexture := rendering.CreateTexture(utils.LoadFile("./assets/arthas.png"))
 	playerActor := actor.NewActor([2]float64{0, 0}, playerTexture, 14, "Purger", true)
-	return player.NewPlayer(playerActor)
-}
+	Animate(playerActor, DefaultClips(playerTexture))
 
-func (playmode *ModeInvincible) InitNPCs() []*actor.Actor {
-	// Initialize the NPC actors
-	scourgeTexture := rendering.CreateTexture(utils.LoadFile("./assets/pudge.png"))
-	scourgeTexture1 := rendering.CreateTexture(utils.LoadFile("./assets/scourge.png"))
+	// The paladin answers the prompt, or cures from afar with Burst of Light
+	paladin := player.NewPlayer(playerActor)
+	paladin.AbilitySlots = []*player.AbilitySlot{player.BurstOfLightSlot()}
+	paladin.TalentTree = talentTree(player.PaladinTalents)
+	return paladin
+}
 
-	npcActor := actor.NewActor([2]float64{200, 200}, scourgeTexture, 4, "Scourge", true)
-	npcActor1 := actor.NewActor([2]float64{400, 200}, scourgeTexture1, 1, "Undead1", true)
-	npcActor2 := actor.NewActor([2]float64{500, 300}, scourgeTexture1, 1, "Undead2", true)
-	npcActor3 := actor.NewActor([2]float64{250, 50}, scourgeTexture1, 1, "Undead3", true)
+// InitNPCs sets up the wave spawner and spawns the first wave of citizens.
+func (playmode *ModeInvincible) InitNPCs() {
+	playmode.Spawner = &Spawner{
+		SpawnPoints: DefaultSpawnPoints(),
+		Archetypes: []*Archetype{
+			{Name: "Citizen", TexturePath: "./assets/scourge.png", Speed: 1, XP: 10},
+			{Name: "Abomination", TexturePath: "./assets/pudge.PNG", Speed: 4, XP: 30},
+		},
+		WaveCount:    5,
+		WaveInterval: 20,
+		BaseCount:    3,
+		CountGrowth:  1,
+	}
+	playmode.WinCondition = WinCondition{Type: SurviveWaves}
 
-	return []*actor.Actor{npcActor, npcActor1, npcActor2, npcActor3}
+	playmode.Spawner.Update(&GameState{}, playmode.Entities)
 }
 
-func (playmode *ModeInvincible) HandleKeyboardInput(
+func (playmode *ModeInvincible) HandleInput(
 	gameState *GameState,
 	player *player.Player,
 	gameActors []*actor.Actor,
-	keys []ebiten.Key) {
-	player.HandleInput(keys)
+	controls *input.Mapper) {
+	player.HandleInput(controls.Movement())
+	if controls.JustPressed(input.CastBurstOfLight) {
+		player.BurstOfLight()
+	}
 	// What if the NPC goes over the player?
 	for _, npcActor := range gameActors {
 		if !npcActor.Draw || !npcActor.CollisionEnabled {
@@ -111,12 +121,49 @@ func (playmode *ModeInvincible) HandleKeyboardInput(
 	}
 }
 
-// TODO: might be better to move this to the main input handler
-func (playmode *ModeInvincible) HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor) {
-	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
+// HandlePlayerInput purges or spares the citizen picked in the dialog, and resumes the game.
+func (playmode *ModeInvincible) HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor, controls *input.Mapper) {
+	switch playmode.Prompt.Update(controls) {
+	case purgeChoice:
 		playmode.Purge(gameState, npcActors, npcActor)
-	}
-	if inpututil.IsKeyJustPressed(ebiten.KeyS) {
+	case spareChoice:
 		playmode.Spare(gameState, npcActors, npcActor)
+	default:
+		return
 	}
+	gameState.PromptPlayer = false
+	gameState.Status = StatusMap[GameStarted]
+}
+
+// Controls adds the Purge and Spare choices to the shared controls.
+func (playmode *ModeInvincible) Controls() []ControlHint {
+	return append(playmode.BasePlayMode.Controls(),
+		ControlHint{Actions: []input.Action{input.Purge}, Description: "Purge the citizen"},
+		ControlHint{Actions: []input.Action{input.Spare}, Description: "Spare the citizen"},
+		ControlHint{Actions: []input.Action{input.CastBurstOfLight}, Description: "Burst of Light the target"},
+	)
+}
+
+// Enter sends the paladin into the night of Stratholme.
+func (playmode *ModeInvincible) Enter(ctx *Context) {
+	playmode.attach(ctx)
+	ctx.Player = playmode.InitPlayer()
+	playmode.InitNPCs()
+	playmode.Prompt = NewChoiceDialog(
+		DialogChoice{Text: "Purge", Action: input.Purge},
+		DialogChoice{Text: "Spare", Action: input.Spare},
+	)
+	playmode.enter(ctx, playmode.Controls())
+	ctx.Lighting = stratholmeLighting(ctx.Player)
+}
+
+// Update runs a tick of the mode.
+func (playmode *ModeInvincible) Update(ctx *Context) {
+	playmode.tick(ctx, playmode)
+}
+
+// Draw draws Stratholme at night.
+func (playmode *ModeInvincible) Draw(screen *ebiten.Image, ctx *Context) {
+	drawStratholme(screen)
+	playmode.BasePlayMode.Draw(screen, ctx)
 }
@@ -0,0 +1,193 @@
+package gameplay
+
+import (
+	"github.com/actor"
+	"github.com/events"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+// PlayMode is a game mode - the game enters it, updates and draws it, and exits it when the game is left.
+type PlayMode interface {
+	// Enter creates the player and the first NPCs.
+	Enter(ctx *Context)
+	// Update runs the game rules for one tick. The game over is checked here too.
+	Update(ctx *Context)
+	// Draw draws the mode's map and world effects below the actors.
+	Draw(screen *ebiten.Image, ctx *Context)
+	// Exit releases what the mode holds outside of the game.
+	Exit(ctx *Context)
+}
+
+// Context is what a play mode works with - the game state, the actors, the events and the input.
+type Context struct {
+	State    *GameState
+	Controls *input.Mapper
+	Delta    float64        // Seconds of the current tick
+	Entities *actor.Manager // Every actor of the game - the player, the NPCs and the mode's own characters
+	Events   *events.Bus    // What happens in the game, for the audio, the HUD and the other listeners
+	Player   *player.Player
+	XPCurve  player.XPCurve      // Experience the player needs for each level, from the settings
+	Builds   map[string][]string // Talents learned in the previous games, by talent tree, from the save file
+
+	// What the game shows of the mode
+	Hints      []ControlHint       // Lines of the controls overlay
+	Bosses     []*Boss             // Bosses with a health bar on the HUD
+	Archetypes []*Archetype        // Kinds of NPCs the mode spawns, summons included, for the console
+	Lighting   *rendering.Lighting // Lights and fog of the map, nil for a map in plain daylight
+	Prompt     *ChoiceDialog       // Drawn over the game while it awaits the player, optional
+}
+
+// NPCs returns the NPCs in the game, bosses included.
+func (ctx *Context) NPCs() []*actor.Actor {
+	return ctx.Entities.WithTag(actor.TagNPC)
+}
+
+// modeRules are the steps of a tick that each mode can override.
+type modeRules interface {
+	InitActors(npcActors []*actor.Actor)
+	SpawnNPCs(gameState *GameState)
+	UpdateWorld(gameState *GameState, gameActors []*actor.Actor, player *player.Player)
+	FindPath(from, to [2]float64) [][2]float64
+	HandleInput(gameState *GameState, player *player.Player, gameActors []*actor.Actor, controls *input.Mapper)
+	HandlePlayerInput(gameState *GameState, npcActors []*actor.Actor, npcActor *actor.Actor, controls *input.Mapper)
+	CheckGameOverAndUpdateState(gameState *GameState, gameActors []*actor.Actor, player *player.Player)
+}
+
+// attach gives the mode the entity manager and the event bus of the game, before it creates its actors.
+func (playmode *BasePlayMode) attach(ctx *Context) {
+	playmode.Entities = ctx.Entities
+	playmode.Events = ctx.Events
+}
+
+// enter adds the mode's player to the game and subscribes the rules to the game's events.
+func (playmode *BasePlayMode) enter(ctx *Context, hints []ControlHint) {
+	publishPlayerEvents(ctx.Events, ctx.Player)
+	publishBossEvents(ctx.Events, playmode.Bosses)
+	publishRemovals(ctx.Events, ctx.Entities)
+	if ctx.XPCurve.Base > 0 {
+		ctx.Player.XPCurve = ctx.XPCurve
+	}
+	if tree := ctx.Player.TalentTree; tree != nil {
+		ctx.Player.SetTalentTree(tree, ctx.Builds[tree.ID])
+	}
+	events.Subscribe(ctx.Events, func(event NPCPurged) { ctx.Player.GainXP(playmode.xpReward(event.NPC)) })
+	events.Subscribe(ctx.Events, func(event NPCSpared) { ctx.Player.GainXP(playmode.xpReward(event.NPC)) })
+	events.Subscribe(ctx.Events, func(event ActorRemoved) {
+		if ctx.State.Target == event.Actor {
+			ctx.State.Target = nil
+		}
+		ctx.Player.Forget(event.Actor)
+	})
+	ctx.Player.Actor.Tag(actor.TagPlayer)
+	ctx.Entities.Add(ctx.Player.Actor)
+	ctx.Entities.Flush()
+	ctx.Hints = hints
+	ctx.Bosses = playmode.Bosses
+	ctx.Prompt = playmode.Prompt
+	ctx.Archetypes = playmode.archetypes()
+}
+
+// archetypes returns the kinds of NPCs of the mode - the spawner's and the summoned ones.
+func (playmode *BasePlayMode) archetypes() []*Archetype {
+	var archetypes []*Archetype
+	if playmode.Spawner != nil {
+		archetypes = append(archetypes, playmode.Spawner.Archetypes...)
+	}
+	return append(archetypes, playmode.Summons...)
+}
+
+// xpReward returns the experience the NPC is worth, by its archetype.
+func (playmode *BasePlayMode) xpReward(npc *actor.Actor) int {
+	for _, archetype := range playmode.archetypes() {
+		if archetype.Name == npc.Archetype {
+			return archetype.XP
+		}
+	}
+	return 0
+}
+
+// Exit releases what the mode holds outside of the game.
+func (playmode *BasePlayMode) Exit(ctx *Context) {
+}
+
+// tick runs the steps of the mode's rules for one tick.
+func (playmode *BasePlayMode) tick(ctx *Context, rules modeRules) {
+	state := ctx.State
+	switch state.Status {
+	case StatusMap[AwaitingUser]:
+		rules.HandlePlayerInput(state, ctx.NPCs(), state.Target, ctx.Controls)
+		return
+	case StatusMap[GameStarted]:
+	default:
+		return
+	}
+
+	// The NPCs spawned during the tick join the game at its end, when the entity manager flushes
+	player := ctx.Player
+	npcs := ctx.NPCs()
+	rules.InitActors(npcs)
+	state.Delta = ctx.Delta
+	state.TimeElapsed += ctx.Delta
+	rules.SpawnNPCs(state)
+	rules.UpdateWorld(state, npcs, player)
+
+	playmode.handleMouseInput(ctx, npcs, rules)
+	if ctx.Controls.JustPressed(input.CycleTarget) {
+		player.CycleTarget(npcs)
+	}
+	if ctx.Controls.Active() || player.Walking() {
+		rules.HandleInput(state, player, npcs, ctx.Controls)
+		player.Actor.SetLimitBounds(rendering.ScreenWidth, rendering.ScreenHeight)
+	} else {
+		player.Actor.ResetMoveDirection()
+	}
+
+	player.Actor.UpdateAnimation(ctx.Delta)
+	for _, npc := range npcs {
+		npc.UpdateAnimation(ctx.Delta)
+	}
+	player.Update(ctx.Delta)
+	removeGone(ctx.Entities, npcs)
+	ctx.Entities.Flush()
+	player.UpdateTarget()
+	player.UpdateAbilitiesDurations()
+
+	rules.CheckGameOverAndUpdateState(state, ctx.NPCs(), player)
+}
+
+// handleMouseInput walks the player to the right-clicked point and targets the left-clicked NPC.
+func (playmode *BasePlayMode) handleMouseInput(ctx *Context, npcs []*actor.Actor, rules modeRules) {
+	player := ctx.Player
+	if point, ok := input.MouseJustPressed(ebiten.MouseButtonRight); ok {
+		rect := player.Actor.GetBoundingRect()
+		// The player's center ends up on the clicked point
+		destination := [2]float64{point[0] - rect.Width/2, point[1] - rect.Height/2}
+		player.WalkTo(rules.FindPath(player.Actor.Position, destination))
+	}
+	if point, ok := input.MouseJustPressed(ebiten.MouseButtonLeft); ok {
+		player.Target = npcAt(npcs, point)
+	}
+}
+
+// npcAt returns the topmost NPC under the point, or nil if there is none.
+func npcAt(npcs []*actor.Actor, point [2]float64) *actor.Actor {
+	for i := len(npcs) - 1; i >= 0; i-- {
+		npc := npcs[i]
+		if npc.Present() && npc.GetBoundingRect().Contains(point) {
+			return npc
+		}
+	}
+	return nil
+}
+
+// removeGone removes the NPCs whose death clip or fade-out is over from the game.
+func removeGone(entities *actor.Manager, npcs []*actor.Actor) {
+	for _, npc := range npcs {
+		if npc.Gone() {
+			entities.Remove(npc)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package gameplay
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/actor"
+)
+
+// NavGrid marks the cells of the map an actor of a given size can't stand on, for A*.
+// Positions are the actor's top-left corner, the same as actor.Actor.Position.
+type NavGrid struct {
+	CellSize float64
+	Columns  int
+	Rows     int
+	blocked  []bool
+}
+
+// NewNavGrid creates a navigation grid for an actor of the given size on a map of the given size.
+func NewNavGrid(width, height, cellSize float64, obstacles []*actor.BoundingRect, agentWidth, agentHeight float64) *NavGrid {
+	grid := &NavGrid{
+		CellSize: cellSize,
+		Columns:  int(math.Ceil(width / cellSize)),
+		Rows:     int(math.Ceil(height / cellSize)),
+	}
+	grid.blocked = make([]bool, grid.Columns*grid.Rows)
+
+	for row := range grid.Rows {
+		for column := range grid.Columns {
+			agentRect := &actor.BoundingRect{
+				PositionX: float64(column) * cellSize,
+				PositionY: float64(row) * cellSize,
+				Width:     agentWidth,
+				Height:    agentHeight,
+			}
+			outOfMap := agentRect.PositionX+agentWidth > width || agentRect.PositionY+agentHeight > height
+			grid.blocked[row*grid.Columns+column] = outOfMap || intersectsAny(agentRect, obstacles)
+		}
+	}
+
+	return grid
+}
+
+func intersectsAny(rect *actor.BoundingRect, obstacles []*actor.BoundingRect) bool {
+	for _, obstacle := range obstacles {
+		if rect.Intersects(obstacle) {
+			return true
+		}
+	}
+	return false
+}
+
+// Blocked reports whether the cell is outside of the grid or taken by an obstacle.
+func (grid *NavGrid) Blocked(column, row int) bool {
+	if column < 0 || row < 0 || column >= grid.Columns || row >= grid.Rows {
+		return true
+	}
+	return grid.blocked[row*grid.Columns+column]
+}
+
+// cellAt returns the cell that contains the position, clamped to the grid.
+func (grid *NavGrid) cellAt(position [2]float64) (int, int) {
+	column := int(math.Round(position[0] / grid.CellSize))
+	row := int(math.Round(position[1] / grid.CellSize))
+	return max(0, min(column, grid.Columns-1)), max(0, min(row, grid.Rows-1))
+}
+
+// FindPath returns the waypoints to the target around the obstacles, or nil if it can't be reached.
+func (grid *NavGrid) FindPath(from, to [2]float64) [][2]float64 {
+	startColumn, startRow := grid.cellAt(from)
+	goalColumn, goalRow := grid.cellAt(to)
+	start := startRow*grid.Columns + startColumn
+	goal := goalRow*grid.Columns + goalColumn
+
+	if grid.blocked[goal] {
+		return nil
+	}
+
+	cameFrom := make(map[int]int)
+	cost := map[int]float64{start: 0}
+	open := &nodeQueue{{cell: start, priority: grid.heuristic(start, goal)}}
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*pathNode).cell
+		if current == goal {
+			return grid.buildPath(cameFrom, current, start, to)
+		}
+
+		column, row := current%grid.Columns, current/grid.Columns
+		for _, direction := range [8][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, 1}, {1, -1}, {-1, 1}, {-1, -1}} {
+			nextColumn, nextRow := column+direction[0], row+direction[1]
+			if grid.Blocked(nextColumn, nextRow) {
+				continue
+			}
+			// Don't cut the corners of obstacles when moving diagonally
+			if direction[0] != 0 && direction[1] != 0 &&
+				(grid.Blocked(column+direction[0], row) || grid.Blocked(column, row+direction[1])) {
+				continue
+			}
+
+			next := nextRow*grid.Columns + nextColumn
+			nextCost := cost[current] + math.Hypot(float64(direction[0]), float64(direction[1]))
+			if knownCost, ok := cost[next]; ok && knownCost <= nextCost {
+				continue
+			}
+			cost[next] = nextCost
+			cameFrom[next] = current
+			heap.Push(open, &pathNode{cell: next, priority: nextCost + grid.heuristic(next, goal)})
+		}
+	}
+
+	return nil
+}
+
+// FindPathNear is FindPath, but to the nearest open cell when the target's is blocked.
+func (grid *NavGrid) FindPathNear(from, to [2]float64) [][2]float64 {
+	column, row := grid.cellAt(to)
+	if grid.Blocked(column, row) {
+		var ok bool
+		if column, row, ok = grid.nearestOpen(column, row); !ok {
+			return nil
+		}
+		to = [2]float64{float64(column) * grid.CellSize, float64(row) * grid.CellSize}
+	}
+	return grid.FindPath(from, to)
+}
+
+// nearestOpen returns the open cell closest to the cell, searching in growing squares around it.
+func (grid *NavGrid) nearestOpen(column, row int) (int, int, bool) {
+	for radius := 1; radius < max(grid.Columns, grid.Rows); radius++ {
+		bestColumn, bestRow, best := 0, 0, math.Inf(1)
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+				if max(abs(dx), abs(dy)) != radius || grid.Blocked(column+dx, row+dy) {
+					continue
+				}
+				if distance := math.Hypot(float64(dx), float64(dy)); distance < best {
+					bestColumn, bestRow, best = column+dx, row+dy, distance
+				}
+			}
+		}
+		if !math.IsInf(best, 1) {
+			return bestColumn, bestRow, true
+		}
+	}
+	return 0, 0, false
+}
+
+func abs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// heuristic is the octile distance between two cells.
+func (grid *NavGrid) heuristic(cell, goal int) float64 {
+	dx := math.Abs(float64(cell%grid.Columns - goal%grid.Columns))
+	dy := math.Abs(float64(cell/grid.Columns - goal/grid.Columns))
+	return dx + dy + (math.Sqrt2-2)*math.Min(dx, dy)
+}
+
+// buildPath returns the cell positions from the start to the goal.
+func (grid *NavGrid) buildPath(cameFrom map[int]int, current, start int, to [2]float64) [][2]float64 {
+	path := [][2]float64{to}
+	for current != start {
+		current = cameFrom[current]
+		if current == start {
+			break
+		}
+		path = append(path, [2]float64{
+			float64(current%grid.Columns) * grid.CellSize,
+			float64(current/grid.Columns) * grid.CellSize,
+		})
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+type pathNode struct {
+	cell     int
+	priority float64
+}
+
+// nodeQueue is the open set of A*, a min-heap ordered by the node priority.
+type nodeQueue []*pathNode
+
+func (queue nodeQueue) Len() int           { return len(queue) }
+func (queue nodeQueue) Less(i, j int) bool { return queue[i].priority < queue[j].priority }
+func (queue nodeQueue) Swap(i, j int)      { queue[i], queue[j] = queue[j], queue[i] }
+func (queue *nodeQueue) Push(node any)     { *queue = append(*queue, node.(*pathNode)) }
+func (queue *nodeQueue) Pop() any {
+	old := *queue
+	node := old[len(old)-1]
+	*queue = old[:len(old)-1]
+	return node
+}
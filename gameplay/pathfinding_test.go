@@ -0,0 +1,99 @@
+package gameplay
+
+import (
+	"testing"
+
+	"github.com/actor"
+)
+
+// newTestGrid creates a 10x10 grid of 10 pixel cells for an actor of one cell.
+func newTestGrid(obstacles ...*actor.BoundingRect) *NavGrid {
+	return NewNavGrid(100, 100, 10, obstacles, 10, 10)
+}
+
+// checkPath fails the test if the path doesn't end at the target or goes through a blocked cell.
+func checkPath(t *testing.T, grid *NavGrid, path [][2]float64, to [2]float64) {
+	t.Helper()
+	if len(path) == 0 {
+		t.Fatal("no path found")
+	}
+	if last := path[len(path)-1]; last != to {
+		t.Errorf("the path ends at %v, want %v", last, to)
+	}
+	for _, waypoint := range path {
+		if column, row := grid.cellAt(waypoint); grid.Blocked(column, row) {
+			t.Errorf("the path goes through the blocked cell at %v", waypoint)
+		}
+	}
+}
+
+func TestFindPathStraight(t *testing.T) {
+	grid := newTestGrid()
+	to := [2]float64{90, 0}
+
+	path := grid.FindPath([2]float64{0, 0}, to)
+
+	checkPath(t, grid, path, to)
+	if len(path) != 9 {
+		t.Errorf("got %d waypoints, want 9: %v", len(path), path)
+	}
+	for _, waypoint := range path {
+		if waypoint[1] != 0 {
+			t.Errorf("the path leaves the row at %v", waypoint)
+		}
+	}
+}
+
+func TestFindPathAroundWall(t *testing.T) {
+	// The wall blocks the 6th column from the top down to the 8th row
+	grid := newTestGrid(&actor.BoundingRect{PositionX: 52, PositionY: 0, Width: 6, Height: 78})
+	to := [2]float64{90, 0}
+
+	path := grid.FindPath([2]float64{0, 0}, to)
+
+	checkPath(t, grid, path, to)
+	passedBelow := false
+	for _, waypoint := range path {
+		if waypoint[0] == 50 && waypoint[1] >= 80 {
+			passedBelow = true
+		}
+	}
+	if !passedBelow {
+		t.Errorf("the path doesn't go around the end of the wall: %v", path)
+	}
+}
+
+func TestFindPathUnreachable(t *testing.T) {
+	// The wall cuts the map in two
+	grid := newTestGrid(&actor.BoundingRect{PositionX: 52, PositionY: 0, Width: 6, Height: 100})
+
+	if path := grid.FindPath([2]float64{0, 0}, [2]float64{90, 0}); path != nil {
+		t.Errorf("found a path across the wall: %v", path)
+	}
+	if path := grid.FindPath([2]float64{0, 0}, [2]float64{50, 50}); path != nil {
+		t.Errorf("found a path into the wall: %v", path)
+	}
+}
+
+func TestFindPathToStart(t *testing.T) {
+	grid := newTestGrid()
+	at := [2]float64{30, 30}
+
+	path := grid.FindPath(at, at)
+
+	if len(path) != 1 || path[0] != at {
+		t.Errorf("got %v, want only the start %v", path, at)
+	}
+}
+
+func TestFindPathNearBlockedTarget(t *testing.T) {
+	// The wall takes the 6th column from the top down to the 8th row
+	grid := newTestGrid(&actor.BoundingRect{PositionX: 52, PositionY: 0, Width: 6, Height: 78})
+
+	path := grid.FindPathNear([2]float64{0, 0}, [2]float64{50, 0})
+
+	checkPath(t, grid, path, [2]float64{40, 0})
+	if len(path) != 4 {
+		t.Errorf("got %d waypoints, want 4 to the open cell next to the target: %v", len(path), path)
+	}
+}
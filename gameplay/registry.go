@@ -0,0 +1,52 @@
+package gameplay
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ModeInfo describes a game mode for the menus, the settings and the console.
+type ModeInfo struct {
+	ID          int    // Number of the mode, used by the settings, the console and the number keys of the menu
+	Name        string // Display name
+	Description string // Blurb shown under the mode list, may span several lines
+	Preview     string // Path of the image shown with the description
+	WinText     string // Shown on the end screen when the player wins
+	LossText    string // Shown on the end screen when the player loses
+	New         func() PlayMode
+}
+
+var modes = map[int]ModeInfo{}
+
+// RegisterMode adds a mode to the registry. It panics if the ID is already taken.
+func RegisterMode(info ModeInfo) {
+	if _, ok := modes[info.ID]; ok {
+		panic(fmt.Sprintf("gameplay: mode %d is registered twice", info.ID))
+	}
+	modes[info.ID] = info
+}
+
+// Modes returns the registered modes, ordered by ID.
+func Modes() []ModeInfo {
+	infos := make([]ModeInfo, 0, len(modes))
+	for _, info := range modes {
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].ID < infos[j].ID })
+	return infos
+}
+
+// Mode returns the info of the mode with the ID, and false if there is no such mode.
+func Mode(id int) (ModeInfo, bool) {
+	info, ok := modes[id]
+	return info, ok
+}
+
+// NewPlayMode creates the mode with the ID, or returns nil if there is no such mode.
+func NewPlayMode(id int) PlayMode {
+	info, ok := modes[id]
+	if !ok {
+		return nil
+	}
+	return info.New()
+}
@@ -0,0 +1,66 @@
+package gameplay
+
+import (
+	"os"
+	"testing"
+
+	"github.com/actor"
+	"github.com/events"
+	"github.com/input"
+	"github.com/utils"
+)
+
+// TestModesEnter starts a headless session of every registered mode.
+func TestModesEnter(t *testing.T) {
+	utils.AssetPath = "../assets"
+
+	for _, info := range Modes() {
+		t.Run(info.Name, func(t *testing.T) {
+			defer func() {
+				if err := recover(); err != nil {
+					t.Fatalf("entering the mode panicked: %v", err)
+				}
+			}()
+
+			ctx := &Context{
+				State:    &GameState{Status: StatusMap[GameStarted]},
+				Controls: input.NewMapper(input.DefaultBindings()),
+				Entities: actor.NewManager(),
+				Events:   events.NewBus(),
+			}
+			info.New().Enter(ctx)
+
+			if ctx.Player == nil {
+				t.Fatal("the mode didn't create the player")
+			}
+			if _, ok := ctx.Entities.Get(ctx.Player.Actor.Id); !ok {
+				t.Error("the player isn't in the entity manager")
+			}
+		})
+	}
+}
+
+func TestNewPlayModeUnknown(t *testing.T) {
+	if playmode := NewPlayMode(-1); playmode != nil {
+		t.Errorf("NewPlayMode(-1) = %T, want nil", playmode)
+	}
+}
+
+func TestModesHaveEndTexts(t *testing.T) {
+	for _, info := range Modes() {
+		if info.WinText == "" || info.LossText == "" {
+			t.Errorf("%s has no win or loss text for the end screen", info.Name)
+		}
+	}
+}
+
+// TestModePreviewsExist checks the previews' paths, with their case, as the home screen leaves a missing one out.
+func TestModePreviewsExist(t *testing.T) {
+	utils.AssetPath = "../assets"
+
+	for _, info := range Modes() {
+		if _, err := os.Stat(utils.AssetFile(info.Preview)); err != nil {
+			t.Errorf("%s has no preview: %v", info.Name, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package gameplay
+
+import (
+	_ "image/png"
+
+	"github.com/actor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rendering"
+	"github.com/utils"
+)
+
+// Archetype describes a kind of NPC that the spawner can emit.
+type Archetype struct {
+	Name        string
+	TexturePath string
+	Speed       float64
+	XP          int          // Experience the player gains for purging or sparing an NPC of the archetype
+	SpriteSheet *SpriteSheet // Animations of the archetype, optional - without it the clips are made from the texture
+	texture     *ebiten.Image
+	clips       map[string]*actor.Animation
+}
+
+// Texture loads the archetype's texture on first use and caches it.
+func (archetype *Archetype) Texture() *ebiten.Image {
+	if archetype.texture == nil {
+		texture, err := rendering.LoadTexture(utils.AssetFile(archetype.TexturePath))
+		if err != nil {
+			logger.Error("loading the texture of the archetype", "archetype", archetype.Name, "err", err)
+			texture = rendering.MissingTexture()
+		}
+		archetype.texture = texture
+	}
+	return archetype.texture
+}
+
+// Clips makes the archetype's animation clips on first use and caches them.
+func (archetype *Archetype) Clips() map[string]*actor.Animation {
+	if archetype.clips == nil {
+		if archetype.SpriteSheet != nil {
+			archetype.clips = archetype.SpriteSheet.LoadClips()
+		} else {
+			archetype.clips = DefaultClips(archetype.Texture())
+		}
+	}
+	return archetype.clips
+}
+
+// NewNPC creates an animated NPC actor of this archetype at the given position.
+func (archetype *Archetype) NewNPC(entities *actor.Manager, position [2]float64) *actor.Actor {
+	npc := entities.New(position, archetype.Texture(), archetype.Speed, archetype.Name, true, actor.TagNPC)
+	npc.Archetype = archetype.Name
+	Animate(npc, archetype.Clips())
+	return npc
+}
+
+// Spawner emits growing waves of NPCs from its spawn points on a fixed schedule.
+type Spawner struct {
+	SpawnPoints  [][2]float64
+	Archetypes   []*Archetype
+	WaveCount    int     // Total number of waves, 0 means the waves never stop
+	WaveInterval float64 // Seconds between two waves
+	BaseCount    int     // Number of NPCs in the first wave
+	CountGrowth  int     // Number of NPCs added to each following wave
+	CurrentWave  int     // Number of the last spawned wave, 0 before the first one
+	nextWaveAt   float64 // Game time in seconds at which the next wave is due
+	spawned      int     // Number of NPCs spawned so far, used to rotate the spawn points
+}
+
+// Done reports whether the spawner has emitted all of its waves.
+func (spawner *Spawner) Done() bool {
+	return spawner.WaveCount > 0 && spawner.CurrentWave >= spawner.WaveCount
+}
+
+// NextWaveIn returns the seconds left until the next wave, or 0 if there is none.
+func (spawner *Spawner) NextWaveIn(timeElapsed float64) float64 {
+	if spawner.Done() {
+		return 0
+	}
+	return max(spawner.nextWaveAt-timeElapsed, 0)
+}
+
+// Update spawns the next wave if it is due and returns the new NPCs.
+func (spawner *Spawner) Update(gameState *GameState, entities *actor.Manager) []*actor.Actor {
+	var npcActors []*actor.Actor
+
+	if !spawner.Done() && gameState.TimeElapsed >= spawner.nextWaveAt {
+		spawner.CurrentWave++
+		spawner.nextWaveAt = gameState.TimeElapsed + spawner.WaveInterval
+		npcActors = spawner.spawnWave(spawner.CurrentWave, entities)
+		logger.Debug("wave spawned", "wave", spawner.CurrentWave, "npcs", len(npcActors), "at", gameState.TimeElapsed)
+	}
+
+	gameState.Wave = spawner.CurrentWave
+	gameState.WaveCount = spawner.WaveCount
+	gameState.NextWaveIn = spawner.NextWaveIn(gameState.TimeElapsed)
+
+	return npcActors
+}
+
+// spawnWave creates the NPCs of the given wave.
+func (spawner *Spawner) spawnWave(wave int, entities *actor.Manager) []*actor.Actor {
+	if len(spawner.SpawnPoints) == 0 || len(spawner.Archetypes) == 0 {
+		return nil
+	}
+
+	count := spawner.BaseCount + (wave-1)*spawner.CountGrowth
+	unlocked := min(wave, len(spawner.Archetypes))
+	npcActors := make([]*actor.Actor, 0, count)
+
+	for range count {
+		spawnPoint := spawner.SpawnPoints[spawner.spawned%len(spawner.SpawnPoints)]
+		archetype := spawner.Archetypes[int(utils.GetRandomNumInRange(0, float64(unlocked)))%unlocked]
+		position := [2]float64{
+			spawnPoint[0] + utils.GetRandomNumInRange(-20, 20),
+			spawnPoint[1] + utils.GetRandomNumInRange(-20, 20),
+		}
+		npcActors = append(npcActors, archetype.NewNPC(entities, position))
+		spawner.spawned++
+	}
+
+	return npcActors
+}
+
+// DefaultSpawnPoints returns spawn points at the edges of the screen, away from the player's start.
+func DefaultSpawnPoints() [][2]float64 {
+	right := float64(rendering.ScreenWidth - 80)
+	bottom := float64(rendering.ScreenHeight - 80)
+
+	return [][2]float64{
+		{right / 2, 40},
+		{right, 40},
+		{right, bottom / 2},
+		{right, bottom},
+		{right / 2, bottom},
+		{40, bottom},
+	}
+}
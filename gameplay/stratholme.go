@@ -0,0 +1,78 @@
+package gameplay
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+// Stratholme at night, shared by the modes played in the burning city.
+var (
+	burningHouses = [][2]float64{{140, 90}, {620, 70}, {330, 400}, {800, 330}}
+	torches       = [][2]float64{{300, 160}, {500, 260}, {700, 180}, {180, 330}, {560, 470}, {900, 480}}
+
+	nightColor      = color.RGBA{0x28, 0x2C, 0x48, 0xFF}
+	fireColor       = color.RGBA{0xFF, 0x78, 0x28, 0xFF}
+	torchColor      = color.RGBA{0xFF, 0xA8, 0x50, 0xFF}
+	playerLight     = color.RGBA{0xA0, 0xA8, 0xC0, 0xFF}
+	houseColor      = color.RGBA{0x4A, 0x34, 0x28, 0xFF}
+	roofColor       = color.RGBA{0x2A, 0x1C, 0x18, 0xFF}
+	flameColor      = color.RGBA{0xFF, 0x90, 0x20, 0xFF}
+	torchPostColor  = color.RGBA{0x3A, 0x2A, 0x1A, 0xFF}
+	streetColor     = color.RGBA{0x50, 0x4C, 0x48, 0xFF}
+	streetLineColor = color.RGBA{0x44, 0x40, 0x3C, 0xFF}
+)
+
+const (
+	houseWidth  = 80
+	houseHeight = 60
+	fogCellSize = 20
+	fogVision   = 200 // Radius in pixels Arthas sees around him
+)
+
+// drawStratholme draws the cobbled streets, the burning houses and the torches.
+func drawStratholme(screen *ebiten.Image) {
+	screen.Fill(streetColor)
+	for y := float32(0); y < rendering.ScreenHeight; y += 40 {
+		rendering.DrawColoredRect(screen, 0, y, rendering.ScreenWidth, 2, streetLineColor)
+	}
+
+	for _, house := range burningHouses {
+		x, y := float32(house[0]), float32(house[1])
+		rendering.DrawColoredRect(screen, x, y, houseWidth, houseHeight, houseColor)
+		rendering.DrawColoredRect(screen, x-6, y-14, houseWidth+12, 18, roofColor)
+		rendering.DrawColoredCircle(screen, x+houseWidth/3, y-14, 8, flameColor)
+		rendering.DrawColoredCircle(screen, x+houseWidth*2/3, y-10, 6, flameColor)
+	}
+	for _, torch := range torches {
+		x, y := float32(torch[0]), float32(torch[1])
+		rendering.DrawColoredRect(screen, x-2, y, 4, 18, torchPostColor)
+		rendering.DrawColoredCircle(screen, x, y, 4, flameColor)
+	}
+}
+
+// stratholmeLighting lights the night with the burning houses, the torches and the player's light.
+func stratholmeLighting(player *player.Player) *rendering.Lighting {
+	playerCenter := func() [2]float64 { return player.Actor.GetBoundingRect().Center() }
+
+	lights := []*rendering.Light{{Radius: 170, Color: playerLight, Follow: playerCenter}}
+	for _, house := range burningHouses {
+		lights = append(lights, &rendering.Light{
+			Position: [2]float64{house[0] + houseWidth/2, house[1]},
+			Radius:   170,
+			Color:    fireColor,
+			Flicker:  0.35,
+		})
+	}
+	for _, torch := range torches {
+		lights = append(lights, &rendering.Light{Position: torch, Radius: 90, Color: torchColor, Flicker: 0.2})
+	}
+
+	return &rendering.Lighting{
+		Ambient: nightColor,
+		Lights:  lights,
+		Fog:     rendering.NewFog(rendering.ScreenWidth, rendering.ScreenHeight, fogCellSize, fogVision, playerCenter),
+	}
+}
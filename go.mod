@@ -12,24 +12,36 @@ replace github.com/utils => ./utils
 
 replace github.com/game => ./game
 
+replace github.com/ui => ./ui
+
 require (
+	github.com/config v0.0.0-00010101000000-000000000000
 	github.com/game v0.0.0-00010101000000-000000000000
+	github.com/gameplay v0.0.0-00010101000000-000000000000
 	github.com/hajimehoshi/ebiten/v2 v2.8.8
+	github.com/joho/godotenv v1.5.1
+	github.com/logging v0.0.0-00010101000000-000000000000
+	github.com/utils v0.0.0-00010101000000-000000000000
 )
 
 require (
 	github.com/actor v0.0.0-00010101000000-000000000000 // indirect
+	github.com/console v0.0.0-00010101000000-000000000000 // indirect
 	github.com/ebitengine/gomobile v0.0.0-20240911145611-4856209ac325 // indirect
 	github.com/ebitengine/hideconsole v1.0.0 // indirect
+	github.com/ebitengine/oto/v3 v3.3.3 // indirect
 	github.com/ebitengine/purego v0.8.0 // indirect
-	github.com/gameplay v0.0.0-00010101000000-000000000000 // indirect
+	github.com/events v0.0.0-00010101000000-000000000000 // indirect
 	github.com/go-text/typesetting v0.2.0 // indirect
 	github.com/google/uuid v1.6.0 // indirect
+	github.com/input v0.0.0-00010101000000-000000000000 // indirect
 	github.com/jezek/xgb v1.1.1 // indirect
-	github.com/joho/godotenv v1.5.1 // indirect
+	github.com/jfreymuth/oggvorbis v1.0.5 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
 	github.com/player v0.0.0-00010101000000-000000000000 // indirect
 	github.com/rendering v0.0.0-00010101000000-000000000000 // indirect
-	github.com/utils v0.0.0-00010101000000-000000000000 // indirect
+	github.com/sound v0.0.0-00010101000000-000000000000 // indirect
+	github.com/ui v0.0.0-00010101000000-000000000000 // indirect
 	golang.org/x/image v0.20.0 // indirect
 	golang.org/x/sync v0.8.0 // indirect
 	golang.org/x/sys v0.25.0 // indirect
@@ -39,3 +51,13 @@ require (
 replace github.com/gameplay => ./gameplay
 
 replace github.com/player => ./player
+
+replace github.com/config => ./config
+
+replace github.com/logging => ./logging
+
+replace github.com/console => ./console
+
+replace github.com/sound => ./sound
+
+replace github.com/events => ./events
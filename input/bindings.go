@@ -0,0 +1,117 @@
+package input
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// BindingsPath is the config file the bindings are saved to.
+const BindingsPath = "config/controls.json"
+
+// GamepadButton is a standard gamepad button, saved by name in the config file.
+type GamepadButton ebiten.StandardGamepadButton
+
+var gamepadButtonNames = map[GamepadButton]string{
+	GamepadButton(ebiten.StandardGamepadButtonRightBottom):      "A",
+	GamepadButton(ebiten.StandardGamepadButtonRightRight):       "B",
+	GamepadButton(ebiten.StandardGamepadButtonRightLeft):        "X",
+	GamepadButton(ebiten.StandardGamepadButtonRightTop):         "Y",
+	GamepadButton(ebiten.StandardGamepadButtonFrontTopLeft):     "LB",
+	GamepadButton(ebiten.StandardGamepadButtonFrontTopRight):    "RB",
+	GamepadButton(ebiten.StandardGamepadButtonFrontBottomLeft):  "LT",
+	GamepadButton(ebiten.StandardGamepadButtonFrontBottomRight): "RT",
+	GamepadButton(ebiten.StandardGamepadButtonCenterLeft):       "Back",
+	GamepadButton(ebiten.StandardGamepadButtonCenterRight):      "Start",
+	GamepadButton(ebiten.StandardGamepadButtonLeftStick):        "LS",
+	GamepadButton(ebiten.StandardGamepadButtonRightStick):       "RS",
+	GamepadButton(ebiten.StandardGamepadButtonLeftTop):          "DPadUp",
+	GamepadButton(ebiten.StandardGamepadButtonLeftBottom):       "DPadDown",
+	GamepadButton(ebiten.StandardGamepadButtonLeftLeft):         "DPadLeft",
+	GamepadButton(ebiten.StandardGamepadButtonLeftRight):        "DPadRight",
+	GamepadButton(ebiten.StandardGamepadButtonCenterCenter):     "Home",
+}
+
+func (button GamepadButton) String() string {
+	return gamepadButtonNames[button]
+}
+
+func (button GamepadButton) MarshalText() ([]byte, error) {
+	name, ok := gamepadButtonNames[button]
+	if !ok {
+		return nil, fmt.Errorf("input: unknown gamepad button: %d", button)
+	}
+	return []byte(name), nil
+}
+
+func (button *GamepadButton) UnmarshalText(text []byte) error {
+	for candidate, name := range gamepadButtonNames {
+		if name == string(text) {
+			*button = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("input: unknown gamepad button: %s", text)
+}
+
+// GamepadAxis is a standard gamepad axis, saved by name in the config file.
+type GamepadAxis ebiten.StandardGamepadAxis
+
+var gamepadAxisNames = map[GamepadAxis]string{
+	GamepadAxis(ebiten.StandardGamepadAxisLeftStickHorizontal):  "LeftStickX",
+	GamepadAxis(ebiten.StandardGamepadAxisLeftStickVertical):    "LeftStickY",
+	GamepadAxis(ebiten.StandardGamepadAxisRightStickHorizontal): "RightStickX",
+	GamepadAxis(ebiten.StandardGamepadAxisRightStickVertical):   "RightStickY",
+}
+
+func (axis GamepadAxis) MarshalText() ([]byte, error) {
+	name, ok := gamepadAxisNames[axis]
+	if !ok {
+		return nil, fmt.Errorf("input: unknown gamepad axis: %d", axis)
+	}
+	return []byte(name), nil
+}
+
+func (axis *GamepadAxis) UnmarshalText(text []byte) error {
+	for candidate, name := range gamepadAxisNames {
+		if name == string(text) {
+			*axis = candidate
+			return nil
+		}
+	}
+	return fmt.Errorf("input: unknown gamepad axis: %s", text)
+}
+
+// LoadBindings reads the bindings from the config file.
+// If the file doesn't exist yet, it returns the default bindings.
+func LoadBindings(path string) (Bindings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultBindings(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	bindings := Bindings{}
+	if err := json.Unmarshal(data, &bindings); err != nil {
+		return nil, fmt.Errorf("input: reading %s: %w", path, err)
+	}
+	return bindings, nil
+}
+
+// SaveBindings writes the bindings to the config file, creating its directory if needed.
+func SaveBindings(path string, bindings Bindings) error {
+	data, err := json.MarshalIndent(bindings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
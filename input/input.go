@@ -0,0 +1,294 @@
+package input
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Action is something the player can do, bound to keys, gamepad buttons and gamepad axes.
+type Action string
+
+const (
+	MoveUp           Action = "MoveUp"
+	MoveDown         Action = "MoveDown"
+	MoveLeft         Action = "MoveLeft"
+	MoveRight        Action = "MoveRight"
+	CastAoE          Action = "CastAoE"
+	CastDeathCoil    Action = "CastDeathCoil"
+	CastBurstOfLight Action = "CastBurstOfLight"
+	CycleTarget      Action = "CycleTarget"
+	Purge            Action = "Purge"
+	Spare            Action = "Spare"
+	Pause            Action = "Pause"
+	Start            Action = "Start"
+	ToggleControls   Action = "ToggleControls"
+	ToggleDebug      Action = "ToggleDebug"
+	ToggleConsole    Action = "ToggleConsole"
+)
+
+// Actions lists the actions in the order they are shown on the controls screen.
+var Actions = []Action{MoveUp, MoveDown, MoveLeft, MoveRight, CastAoE, CastDeathCoil, CastBurstOfLight, CycleTarget, Purge, Spare, Pause, Start, ToggleControls, ToggleDebug, ToggleConsole}
+
+// AxisBinding binds an action to one direction of a gamepad axis.
+type AxisBinding struct {
+	Axis      GamepadAxis `json:"axis"`
+	Direction float64     `json:"direction"` // 1 for the positive side of the axis, -1 for the negative side
+}
+
+// Binding holds the inputs that trigger an action. Any of them triggers it.
+type Binding struct {
+	Keys    []ebiten.Key    `json:"keys"`
+	Buttons []GamepadButton `json:"buttons"`
+	Axes    []AxisBinding   `json:"axes,omitempty"`
+}
+
+// Bindings maps each action to its inputs.
+type Bindings map[Action]*Binding
+
+// DefaultBindings returns the arrow keys and the left stick for movement,
+// and the keys the game has always used for the other actions.
+// Pause and Start share the gamepad's start button, see canShare.
+func DefaultBindings() Bindings {
+	return Bindings{
+		MoveUp: {
+			Keys:    []ebiten.Key{ebiten.KeyArrowUp},
+			Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonLeftTop)},
+			Axes:    []AxisBinding{{Axis: GamepadAxis(ebiten.StandardGamepadAxisLeftStickVertical), Direction: -1}},
+		},
+		MoveDown: {
+			Keys:    []ebiten.Key{ebiten.KeyArrowDown},
+			Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonLeftBottom)},
+			Axes:    []AxisBinding{{Axis: GamepadAxis(ebiten.StandardGamepadAxisLeftStickVertical), Direction: 1}},
+		},
+		MoveLeft: {
+			Keys:    []ebiten.Key{ebiten.KeyArrowLeft},
+			Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonLeftLeft)},
+			Axes:    []AxisBinding{{Axis: GamepadAxis(ebiten.StandardGamepadAxisLeftStickHorizontal), Direction: -1}},
+		},
+		MoveRight: {
+			Keys:    []ebiten.Key{ebiten.KeyArrowRight},
+			Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonLeftRight)},
+			Axes:    []AxisBinding{{Axis: GamepadAxis(ebiten.StandardGamepadAxisLeftStickHorizontal), Direction: 1}},
+		},
+		CastAoE:          {Keys: []ebiten.Key{ebiten.KeyD}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonRightLeft)}},
+		CastDeathCoil:    {Keys: []ebiten.Key{ebiten.KeyC}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonRightTop)}},
+		CastBurstOfLight: {Keys: []ebiten.Key{ebiten.KeyB}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonFrontTopRight)}},
+		CycleTarget:      {Keys: []ebiten.Key{ebiten.KeyTab}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonFrontTopLeft)}},
+		Purge:            {Keys: []ebiten.Key{ebiten.KeyP}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonRightBottom)}},
+		Spare:            {Keys: []ebiten.Key{ebiten.KeyS}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonRightRight)}},
+		Pause:            {Keys: []ebiten.Key{ebiten.KeyEscape}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonCenterRight)}},
+		Start:            {Keys: []ebiten.Key{ebiten.KeySpace}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonCenterRight)}},
+		ToggleControls:   {Keys: []ebiten.Key{ebiten.KeyH}, Buttons: []GamepadButton{GamepadButton(ebiten.StandardGamepadButtonCenterLeft)}},
+		ToggleDebug:      {Keys: []ebiten.Key{ebiten.KeyF3}},
+		ToggleConsole:    {Keys: []ebiten.Key{ebiten.KeyBackquote}},
+	}
+}
+
+// Mapper turns the state of the keyboard and the gamepads into actions.
+type Mapper struct {
+	Bindings Bindings
+	DeadZone float64 // Axis values closer to 0 than this are ignored
+}
+
+// NewMapper creates a mapper with the given bindings.
+// The actions missing from the bindings get their default inputs.
+func NewMapper(bindings Bindings) *Mapper {
+	for action, binding := range DefaultBindings() {
+		if _, ok := bindings[action]; !ok {
+			bindings[action] = binding
+		}
+	}
+	return &Mapper{Bindings: bindings, DeadZone: 0.2}
+}
+
+// Pressed reports whether any key or gamepad button bound to the action is held down,
+// or any bound axis is pushed past the dead zone.
+func (mapper *Mapper) Pressed(action Action) bool {
+	return mapper.Value(action) > 0
+}
+
+// JustPressed reports whether a key or gamepad button bound to the action was pressed in this tick.
+func (mapper *Mapper) JustPressed(action Action) bool {
+	binding, ok := mapper.Bindings[action]
+	if !ok {
+		return false
+	}
+	for _, key := range binding.Keys {
+		if inpututil.IsKeyJustPressed(key) {
+			return true
+		}
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		for _, button := range binding.Buttons {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButton(button)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Value returns how strongly the action is triggered, from 0 to 1.
+// Keys and buttons are 1 while held down, and the axes give the analog value past the dead zone.
+func (mapper *Mapper) Value(action Action) float64 {
+	binding, ok := mapper.Bindings[action]
+	if !ok {
+		return 0
+	}
+	for _, key := range binding.Keys {
+		if ebiten.IsKeyPressed(key) {
+			return 1
+		}
+	}
+
+	value := 0.0
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for _, button := range binding.Buttons {
+			if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButton(button)) {
+				return 1
+			}
+		}
+		for _, axis := range binding.Axes {
+			axisValue := ebiten.StandardGamepadAxisValue(id, ebiten.StandardGamepadAxis(axis.Axis)) * axis.Direction
+			if axisValue > mapper.DeadZone {
+				value = max(value, axisValue)
+			}
+		}
+	}
+	return min(value, 1)
+}
+
+// Movement returns the direction the player wants to move in, built from the four move actions.
+// Its length is between 0 and 1 - smaller when the stick is only pushed part of the way.
+func (mapper *Mapper) Movement() [2]float64 {
+	dx := mapper.Value(MoveRight) - mapper.Value(MoveLeft)
+	dy := mapper.Value(MoveDown) - mapper.Value(MoveUp)
+
+	length := math.Hypot(dx, dy)
+	if length > 1 {
+		dx, dy = dx/length, dy/length
+	}
+	return [2]float64{dx, dy}
+}
+
+// Moving reports whether any of the move actions is triggered.
+func (mapper *Mapper) Moving() bool {
+	movement := mapper.Movement()
+	return movement[0] != 0 || movement[1] != 0
+}
+
+// Active reports whether the player is moving or holding down the input of any action.
+func (mapper *Mapper) Active() bool {
+	for action := range mapper.Bindings {
+		if mapper.Pressed(action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Label returns the first key and gamepad button bound to the action, for the control hints.
+func (mapper *Mapper) Label(action Action) string {
+	binding, ok := mapper.Bindings[action]
+	if !ok {
+		return ""
+	}
+	labels := make([]string, 0, 2)
+	if keyLabel := mapper.KeyLabel(action); keyLabel != "" {
+		labels = append(labels, keyLabel)
+	}
+	if len(binding.Buttons) > 0 {
+		labels = append(labels, binding.Buttons[0].String())
+	}
+	return strings.Join(labels, " / ")
+}
+
+// KeyLabel returns the first key bound to the action, or an empty string if it has no keys.
+func (mapper *Mapper) KeyLabel(action Action) string {
+	binding, ok := mapper.Bindings[action]
+	if !ok || len(binding.Keys) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(binding.Keys[0].String(), "Arrow")
+}
+
+// RebindKey replaces the keys bound to the action with the key. Its gamepad buttons and axes are kept.
+// The key is taken from the other actions bound to it, which are returned, unless they can share it.
+func (mapper *Mapper) RebindKey(action Action, key ebiten.Key) []Action {
+	taken := unbind(mapper, action, func(binding *Binding) *[]ebiten.Key { return &binding.Keys }, key)
+	mapper.binding(action).Keys = []ebiten.Key{key}
+	return taken
+}
+
+// RebindButton replaces the gamepad buttons bound to the action with the button. Its keys and axes are kept.
+// The button is taken from the other actions bound to it, which are returned, unless they can share it.
+func (mapper *Mapper) RebindButton(action Action, button ebiten.StandardGamepadButton) []Action {
+	taken := unbind(mapper, action, func(binding *Binding) *[]GamepadButton { return &binding.Buttons }, GamepadButton(button))
+	mapper.binding(action).Buttons = []GamepadButton{GamepadButton(button)}
+	return taken
+}
+
+// unbind removes the input from the actions other than the action, and returns the actions it was removed from.
+func unbind[T comparable](mapper *Mapper, action Action, inputs func(binding *Binding) *[]T, input T) []Action {
+	var taken []Action
+	for _, other := range Actions {
+		binding, ok := mapper.Bindings[other]
+		if other == action || !ok || canShare(action, other) {
+			continue
+		}
+		if bound := inputs(binding); slices.Contains(*bound, input) {
+			*bound = slices.DeleteFunc(*bound, func(candidate T) bool { return candidate == input })
+			taken = append(taken, other)
+		}
+	}
+	return taken
+}
+
+// canShare reports whether the two actions can be bound to the same input, as they are read on different screens.
+// Start is only read on the home screen and Pause only in the game, and the game fades in after Start,
+// so the press that starts it isn't read as a pause.
+func canShare(first, second Action) bool {
+	return (first == Start && second == Pause) || (first == Pause && second == Start)
+}
+
+func (mapper *Mapper) binding(action Action) *Binding {
+	binding, ok := mapper.Bindings[action]
+	if !ok {
+		binding = &Binding{}
+		mapper.Bindings[action] = binding
+	}
+	return binding
+}
+
+// MouseJustPressed returns the cursor position if the mouse button was pressed in this tick.
+// The mouse isn't bound to actions - right click moves and left click targets.
+func MouseJustPressed(button ebiten.MouseButton) ([2]float64, bool) {
+	if !inpututil.IsMouseButtonJustPressed(button) {
+		return [2]float64{}, false
+	}
+	x, y := ebiten.CursorPosition()
+	return [2]float64{float64(x), float64(y)}, true
+}
+
+// JustPressedInput returns the key or the standard gamepad button pressed in this tick, for rebinding.
+// isKey and isButton report which one was pressed - both are false if nothing was.
+func JustPressedInput() (key ebiten.Key, button ebiten.StandardGamepadButton, isKey, isButton bool) {
+	if keys := inpututil.AppendJustPressedKeys(nil); len(keys) > 0 {
+		return keys[0], 0, true, false
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		for button := range gamepadButtonNames {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButton(button)) {
+				return 0, ebiten.StandardGamepadButton(button), false, true
+			}
+		}
+	}
+	return 0, 0, false, false
+}
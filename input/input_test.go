@@ -0,0 +1,36 @@
+package input
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+func TestRebindKeyTakesTheKey(t *testing.T) {
+	mapper := NewMapper(DefaultBindings())
+
+	taken := mapper.RebindKey(CastAoE, ebiten.KeyP)
+
+	if !slices.Equal(taken, []Action{Purge}) {
+		t.Errorf("the key was taken from %v, want only Purge", taken)
+	}
+	if len(mapper.Bindings[Purge].Keys) != 0 {
+		t.Errorf("Purge is still bound to %v", mapper.Bindings[Purge].Keys)
+	}
+	if !slices.Equal(mapper.Bindings[CastAoE].Keys, []ebiten.Key{ebiten.KeyP}) {
+		t.Errorf("CastAoE is bound to %v, want P", mapper.Bindings[CastAoE].Keys)
+	}
+}
+
+func TestRebindButtonKeepsSharedButtons(t *testing.T) {
+	mapper := NewMapper(DefaultBindings())
+	start := ebiten.StandardGamepadButtonCenterRight
+
+	if taken := mapper.RebindButton(Start, start); len(taken) != 0 {
+		t.Errorf("the start button was taken from %v, but Pause can share it", taken)
+	}
+	if taken := mapper.RebindButton(Purge, start); !slices.Equal(taken, []Action{Pause, Start}) {
+		t.Errorf("the start button was taken from %v, want Pause and Start", taken)
+	}
+}
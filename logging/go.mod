@@ -0,0 +1,3 @@
+module github.com/logging
+
+go 1.24.2
@@ -0,0 +1,176 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Subsystems that log. Each one has its own level, see Configure.
+const (
+	Game      = "game"
+	Gameplay  = "gameplay"
+	Actor     = "actor"
+	Player    = "player"
+	Input     = "input"
+	Rendering = "rendering"
+	Config    = "config"
+	Sound     = "sound"
+)
+
+// Subsystems lists the subsystems in the order they are shown in the console.
+var Subsystems = []string{Game, Gameplay, Actor, Player, Input, Rendering, Config, Sound}
+
+// LevelOff turns a subsystem's logging off.
+const LevelOff = slog.Level(100)
+
+// DefaultSpec is the logging used when nothing configures it - info and above for every subsystem.
+const DefaultSpec = "info"
+
+var (
+	mutex        sync.RWMutex
+	defaultLevel = slog.LevelInfo
+	levels       = map[string]slog.Level{}
+	// output writes the records as text, the subsystem handlers do the filtering
+	output slog.Handler = slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+)
+
+// For returns the logger of a subsystem. It can be created before Configure, the levels are checked on each record.
+func For(subsystem string) *slog.Logger {
+	return slog.New(&subsystemHandler{subsystem: subsystem, handler: output.WithAttrs([]slog.Attr{slog.String("subsystem", subsystem)})})
+}
+
+// Configure sets the levels from a spec, a comma separated list of "level" entries, which set the level of every subsystem,
+// and "subsystem=level" entries. The levels are debug, info, warn, error and off.
+// For example "warn,gameplay=debug,actor=off".
+func Configure(spec string) error {
+	fallback, subsystemLevels, err := ParseSpec(spec)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	defaultLevel = fallback
+	levels = subsystemLevels
+	return nil
+}
+
+// ParseSpec parses a logging spec, see Configure. It returns the level of the subsystems without their own level.
+func ParseSpec(spec string) (slog.Level, map[string]slog.Level, error) {
+	fallback := slog.LevelInfo
+	subsystemLevels := map[string]slog.Level{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		subsystem, levelName, found := strings.Cut(entry, "=")
+		if !found {
+			levelName = subsystem
+		}
+		level, err := parseLevel(levelName)
+		if err != nil {
+			return fallback, nil, err
+		}
+		if !found {
+			fallback = level
+			continue
+		}
+		if !known(subsystem) {
+			return fallback, nil, fmt.Errorf("logging: unknown subsystem %q, pick one of %s", subsystem, strings.Join(Subsystems, ", "))
+		}
+		subsystemLevels[subsystem] = level
+	}
+	return fallback, subsystemLevels, nil
+}
+
+// SetLevel changes the level of one subsystem while the game runs.
+func SetLevel(subsystem, levelName string) error {
+	if !known(subsystem) {
+		return fmt.Errorf("logging: unknown subsystem %q, pick one of %s", subsystem, strings.Join(Subsystems, ", "))
+	}
+	level, err := parseLevel(levelName)
+	if err != nil {
+		return err
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	levels[subsystem] = level
+	return nil
+}
+
+// Spec returns the current levels in the format of Configure.
+func Spec() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	entries := []string{levelName(defaultLevel)}
+	for _, subsystem := range Subsystems {
+		if level, ok := levels[subsystem]; ok {
+			entries = append(entries, subsystem+"="+levelName(level))
+		}
+	}
+	return strings.Join(entries, ",")
+}
+
+func known(subsystem string) bool {
+	for _, name := range Subsystems {
+		if name == subsystem {
+			return true
+		}
+	}
+	return false
+}
+
+func level(subsystem string) slog.Level {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	if level, ok := levels[subsystem]; ok {
+		return level
+	}
+	return defaultLevel
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	if strings.EqualFold(name, "off") {
+		return LevelOff, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(name)); err != nil {
+		return level, fmt.Errorf("logging: unknown level %q, pick one of debug, info, warn, error, off", name)
+	}
+	return level, nil
+}
+
+func levelName(level slog.Level) string {
+	if level >= LevelOff {
+		return "off"
+	}
+	return strings.ToLower(level.String())
+}
+
+// subsystemHandler drops the records below the level of its subsystem.
+type subsystemHandler struct {
+	subsystem string
+	handler   slog.Handler
+}
+
+func (handler *subsystemHandler) Enabled(ctx context.Context, recordLevel slog.Level) bool {
+	return recordLevel >= level(handler.subsystem) && handler.handler.Enabled(ctx, recordLevel)
+}
+
+func (handler *subsystemHandler) Handle(ctx context.Context, record slog.Record) error {
+	return handler.handler.Handle(ctx, record)
+}
+
+func (handler *subsystemHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &subsystemHandler{subsystem: handler.subsystem, handler: handler.handler.WithAttrs(attrs)}
+}
+
+func (handler *subsystemHandler) WithGroup(name string) slog.Handler {
+	return &subsystemHandler{subsystem: handler.subsystem, handler: handler.handler.WithGroup(name)}
+}
@@ -1,38 +1,68 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	_ "image/png"
-	"log"
+	"io/fs"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 
+	"github.com/config"
 	"github.com/game"
+	"github.com/gameplay"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/logging"
+	"github.com/utils"
 )
 
+var logger = logging.For(logging.Config)
+
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		fmt.Println("Error loading .env file")
-		return
+	// The .env file is optional, its variables are one of the configuration layers
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("loading the .env file", "err", err)
 	}
 
-	debugEnabled := os.Getenv("DEBUG")
-	debug := false
-	if debugEnabled == "TRUE" {
-		debug = true
+	settings, err := config.Load(os.Args[1:])
+	if err == nil {
+		err = validateMode(settings.Mode)
 	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid settings:")
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// The spec was checked by config.Load
+	logging.Configure(settings.Log)
+	logger.Debug("settings loaded", "settings", fmt.Sprintf("%+v", settings))
 
-	ebiten.SetWindowSize(game.ScreenWidth*2, game.ScreenHeight*2)
-	ebiten.SetWindowTitle("Animation (Ebitengine Demo)")
-	// TODO: proper error handling
+	utils.AssetPath = settings.AssetPath
+	utils.Seed(settings.Seed)
 
-	// npcActor4 := actor.NewActor([2]float64{300, 100}, scourgeTexture1, 1)
-	// npcActor5 := actor.NewActor([2]float64{300, 300}, scourgeTexture1, 0.5)
+	ebiten.SetWindowSize(int(game.ScreenWidth*settings.Scale), int(game.ScreenHeight*settings.Scale))
+	ebiten.SetWindowTitle("Scourge Hunt")
+	ebiten.SetFullscreen(settings.Fullscreen)
+	ebiten.SetTPS(settings.TPS)
 
-	if err := ebiten.RunGame(game.NewGame(debug)); err != nil {
-		log.Fatal(err)
+	if err := ebiten.RunGame(game.NewGame(settings)); err != nil {
+		logging.For(logging.Game).Error("the game stopped", "err", err)
+		os.Exit(1)
+	}
+}
+
+// validateMode checks that the configured mode is one of the game modes, and lists them if it isn't.
+func validateMode(mode int) error {
+	if _, ok := gameplay.Mode(mode); ok {
+		return nil
+	}
+	modes := make([]string, 0, len(gameplay.Modes()))
+	for _, info := range gameplay.Modes() {
+		modes = append(modes, strconv.Itoa(info.ID)+" ("+info.Name+")")
 	}
+	return fmt.Errorf("config: mode %d doesn't exist, pick one of %s", mode, strings.Join(modes, ", "))
 }
@@ -0,0 +1,68 @@
+package player
+
+import "math"
+
+// Stats the player gains with each level past the first.
+const (
+	healthPerLevel = 20   // Maximum health
+	manaPerLevel   = 10   // Maximum mana
+	damagePerLevel = 0.15 // Share of the base ability damage
+	speedPerLevel  = 0.05 // Share of the base movement speed
+)
+
+// XPCurve gives the experience the player needs for each level.
+// The first level-up takes Base experience, and each following one takes Growth times more than the previous.
+type XPCurve struct {
+	Base   int
+	Growth float64
+}
+
+// DefaultXPCurve returns the curve of the default settings - 40 experience for level 2, 60 for level 3, 90 for level 4...
+func DefaultXPCurve() XPCurve {
+	return XPCurve{Base: 40, Growth: 1.5}
+}
+
+// Needed returns the experience needed to go from the level to the next one.
+func (curve XPCurve) Needed(level int) int {
+	return max(int(math.Round(float64(curve.Base)*math.Pow(curve.Growth, float64(level-1)))), 1)
+}
+
+// XPNeeded returns the experience the player needs for the next level.
+func (p *Player) XPNeeded() int {
+	return p.XPCurve.Needed(p.Level)
+}
+
+// GainXP adds experience, and levels the player up as many times as it adds up to.
+func (p *Player) GainXP(amount int) {
+	p.XP += amount
+	for p.XP >= p.XPNeeded() {
+		p.XP -= p.XPNeeded()
+		p.LevelUp()
+	}
+}
+
+// DamageMultiplier returns the factor of the ability damage at the player's level.
+func (p *Player) DamageMultiplier() float64 {
+	return 1 + damagePerLevel*float64(p.Level-1)
+}
+
+// MoveSpeed returns the player's movement speed at their level.
+func (p *Player) MoveSpeed() float64 {
+	return p.BaseSpeed * (1 + speedPerLevel*float64(p.Level-1))
+}
+
+// LevelUp increases the player's level, grows their maximum health, maximum mana and speed,
+// refills their health and mana and gives them a talent point.
+func (p *Player) LevelUp() {
+	p.Level++
+	p.TalentPoints++
+	p.followBuild()
+	p.MaxHealth += healthPerLevel
+	p.MaxMana += manaPerLevel
+	p.Health = p.MaxHealth
+	p.Mana = p.MaxMana
+	p.Actor.Speed = p.MoveSpeed()
+	if p.OnLevelUp != nil {
+		p.OnLevelUp(p.Level)
+	}
+}
@@ -0,0 +1,87 @@
+package player
+
+import (
+	"testing"
+
+	"github.com/actor"
+)
+
+// newTestPlayer creates a level 1 player with the curve, without the textures of the abilities.
+func newTestPlayer(curve XPCurve) *Player {
+	return &Player{
+		Actor:     &actor.Actor{},
+		Level:     1,
+		XPCurve:   curve,
+		Health:    50,
+		MaxHealth: 100,
+		Mana:      20,
+		MaxMana:   100,
+		BaseSpeed: 2,
+	}
+}
+
+func TestDefaultXPCurve(t *testing.T) {
+	curve := DefaultXPCurve()
+	for level, want := range map[int]int{1: 40, 2: 60, 3: 90} {
+		if got := curve.Needed(level); got != want {
+			t.Errorf("Needed(%d) = %d, want %d", level, got, want)
+		}
+	}
+}
+
+func TestGainXPLevelsUpSeveralTimes(t *testing.T) {
+	p := newTestPlayer(DefaultXPCurve())
+	var levels []int
+	p.OnLevelUp = func(level int) { levels = append(levels, level) }
+
+	// 40 + 60 + 90 to reach level 4, and 5 more
+	p.GainXP(195)
+
+	if p.Level != 4 || p.XP != 5 {
+		t.Errorf("got level %d with %d experience, want level 4 with 5", p.Level, p.XP)
+	}
+	if len(levels) != 3 || levels[2] != 4 {
+		t.Errorf("OnLevelUp got the levels %v, want 2, 3 and 4", levels)
+	}
+	if p.TalentPoints != 3 {
+		t.Errorf("got %d talent points, want 3", p.TalentPoints)
+	}
+	if p.MaxHealth != 160 || p.Health != p.MaxHealth || p.MaxMana != 130 || p.Mana != p.MaxMana {
+		t.Errorf("got %d/%d health and %d/%d mana, want both refilled to 160 and 130",
+			p.Health, p.MaxHealth, p.Mana, p.MaxMana)
+	}
+	if p.Actor.Speed != p.MoveSpeed() {
+		t.Errorf("the actor's speed is %g, want the level's %g", p.Actor.Speed, p.MoveSpeed())
+	}
+}
+
+func TestGainXPBelowNextLevel(t *testing.T) {
+	p := newTestPlayer(DefaultXPCurve())
+
+	p.GainXP(39)
+
+	if p.Level != 1 || p.XP != 39 {
+		t.Errorf("got level %d with %d experience, want level 1 with 39", p.Level, p.XP)
+	}
+}
+
+func TestGainXPWithZeroCurve(t *testing.T) {
+	// Each level takes at least 1 experience, so a curve of zeros levels up once per point instead of looping forever
+	tests := []struct {
+		curve  XPCurve
+		amount int
+		level  int
+	}{
+		{XPCurve{}, 5, 6},
+		{XPCurve{Base: 40}, 45, 7},
+	}
+	for _, test := range tests {
+		p := newTestPlayer(test.curve)
+
+		p.GainXP(test.amount)
+
+		if p.Level != test.level || p.XP != 0 {
+			t.Errorf("%+v: got level %d with %d experience, want level %d with 0", test.curve, p.Level, p.XP, test.level)
+		}
+	}
+}
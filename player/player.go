@@ -1,8 +1,6 @@
 package player
 
 import (
-	"time"
-
 	"github.com/actor"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -25,69 +23,188 @@ const (
 	BurstOfLightType  AbilityType = "SingleTargetHeal"
 )
 
+// AbilitySlot is an ability on the player's action bar, with its input action and cooldown.
+type AbilitySlot struct {
+	Type         AbilityType
+	Name         string
+	Action       string        // Name of the input action that casts the ability
+	Cooldown     float64       // Seconds before the ability can be cast again
+	ManaCost     int           // Mana spent on each cast
+	Icon         *ebiten.Image // Icon of the action bar button
+	cooldownLeft float64       // Seconds of play until the ability can be cast again, counted down by Player.Update
+	reduction    float64       // Share of the cooldown taken off by the player's talents
+}
+
+// CooldownRemaining returns the number of seconds until the ability can be cast again.
+// The cooldown only runs while the game does, not while it's paused or awaiting the player.
+func (slot *AbilitySlot) CooldownRemaining() float64 {
+	return slot.cooldownLeft
+}
+
+// EffectiveCooldown returns the cooldown of the ability with the player's talents, in seconds.
+func (slot *AbilitySlot) EffectiveCooldown() float64 {
+	return slot.Cooldown * (1 - slot.reduction)
+}
+
 type Ability struct {
 	Actor     *actor.Actor
-	Duration  float64     // Duration in seconds for the Death and Decay ability
-	Type      AbilityType // Type of the ability, e.g., "AoE", "Damage", "Heal"
-	StartTime time.Time   // Time when the ability was activated
+	Duration  float64      // Duration in seconds for the Death and Decay ability
+	Type      AbilityType  // Type of the ability, e.g., "AoE", "Damage", "Heal"
+	StartTime float64      // Player's clock when the ability was activated, see Player.Update
+	Target    *actor.Actor // Actor a projectile flies to, nil for the abilities placed on the ground
+	Bounces   int          // NPCs a projectile jumps to after its target, see Bounce
 }
 
 // Player represents the player character in the game.
 type Player struct {
-	Actor     *actor.Actor // The actor representing the player
-	Abilities []*Ability   // The Area of Effect (AoE) actor for the player
-	Health    int          // Player's health
-	Mana      int          // Player's mana
-	Level     int          // Player's level
-	Target    *actor.Actor // The current target of the player
+	Actor        *actor.Actor                  // The actor representing the player
+	Abilities    []*Ability                    // The Area of Effect (AoE) actor for the player
+	AbilitySlots []*AbilitySlot                // The abilities on the player's action bar
+	Health       int                           // Player's health
+	MaxHealth    int                           // Player's maximum health
+	Mana         int                           // Player's mana
+	MaxMana      int                           // Player's maximum mana
+	ManaRegen    float64                       // Mana regenerated per second
+	Level        int                           // Player's level
+	XP           int                           // Experience gathered towards the next level
+	XPCurve      XPCurve                       // Experience needed for each level
+	BaseSpeed    float64                       // Movement speed at level 1, see MoveSpeed
+	TalentTree   *TalentTree                   // Talents of the player's path, nil for a hero without talents
+	TalentPoints int                           // Points left to spend on talents, one for each level-up
+	Target       *actor.Actor                  // The current target of the player
+	God          bool                          // The player takes no damage, set from the console
+	OnCast       func(abilityType AbilityType) // Called when an ability is cast, e.g. to play its sound, optional
+	OnDamaged    func(amount int)              // Called when the player takes damage, optional
+	OnLevelUp    func(level int)               // Called when the player levels up, optional
+	manaBuffer   float64                       // Regenerated mana that doesn't add up to a whole point yet
+	clock        float64                       // Seconds of play, advanced by Update
+	talentRanks  map[string]int                // Rank of each learned talent, by ID
+	talentBuild  []string                      // Talents in the order they are learned, see SetTalentTree
+	learned      int                           // Number of the build's talents learned in this game
+}
+
+// aoeTexture is the Death and Decay texture, loaded on the first cast and shared by all casts.
+var aoeTexture *ebiten.Image
+
+func loadAoETexture() *ebiten.Image {
+	if aoeTexture == nil {
+		aoeTexture = rendering.CreateTexture(utils.LoadFile("./assets/circle1.png"))
+	}
+	return aoeTexture
+}
+
+// scaledAoETextures are the textures of the bigger Death and Decays, by scale.
+var scaledAoETextures = map[float64]*ebiten.Image{}
+
+// scaledAoETexture returns the Death and Decay texture scaled up, the radius of the AoE grows with it.
+func scaledAoETexture(scale float64) *ebiten.Image {
+	if scale == 1 {
+		return loadAoETexture()
+	}
+	if texture, ok := scaledAoETextures[scale]; ok {
+		return texture
+	}
+	texture := rendering.ScaleTexture(loadAoETexture(), scale)
+	scaledAoETextures[scale] = texture
+	return texture
 }
 
 // NewPlayer creates a new Player instance with the given actor and AoE actor.
+// The action bar starts with Death and Decay, modes without it can replace the AbilitySlots.
 func NewPlayer(actor *actor.Actor) *Player {
 	return &Player{
-		Actor:  actor,
-		Health: 100, // Default health
-		Mana:   50,  // Default mana
-		Level:  1,   // Starting level
+		Actor:     actor,
+		Health:    100, // Default health
+		MaxHealth: 100,
+		Mana:      50, // Default mana
+		MaxMana:   50,
+		ManaRegen: 2,
+		Level:     1, // Starting level
+		XPCurve:   DefaultXPCurve(),
+		BaseSpeed: actor.Speed,
+		AbilitySlots: []*AbilitySlot{
+			{Type: DeathAndDecayType, Name: "Death and Decay", Action: "CastAoE", Cooldown: 4, ManaCost: 10, Icon: loadAoETexture()},
+		},
 	}
 }
 
-// HandleInput processes the input from the user and updates the actor's movement direction accordingly.
-// It takes a slice of ebiten.Key values representing the keys currently being pressed.
-// The function resets the actor's movement direction, checks which keys are pressed, and updates
-// the movement direction (MoveDirectionX and MoveDirectionY) based on the arrow keys.
-// Finally, it calculates the new position and moves the actor in the specified direction.
-func (player *Player) HandleInput(inputDeviceActionButtonNames []ebiten.Key) {
-	actor := player.Actor
-	actor.ResetMoveDirection()
-
-	for _, key := range inputDeviceActionButtonNames {
-		if ebiten.IsKeyPressed(key) {
-			switch key {
-			case ebiten.KeyArrowLeft:
-				actor.MoveDirectionX = -1
-			case ebiten.KeyArrowRight:
-				actor.MoveDirectionX = 1
-			case ebiten.KeyArrowUp:
-				actor.MoveDirectionY = -1
-			case ebiten.KeyArrowDown:
-				actor.MoveDirectionY = 1
-			}
+// Slot returns the action bar slot of the ability type, or nil if the player doesn't have it.
+func (p *Player) Slot(abilityType AbilityType) *AbilitySlot {
+	for _, slot := range p.AbilitySlots {
+		if slot.Type == abilityType {
+			return slot
 		}
 	}
+	return nil
+}
 
-	newPosition := [2]float64{actor.MoveDirectionX, actor.MoveDirectionY}
-	actor.MoveIn(newPosition)
+// CanCast reports whether the ability is off cooldown and the player has enough mana for it.
+func (p *Player) CanCast(abilityType AbilityType) bool {
+	slot := p.Slot(abilityType)
+	return slot != nil && slot.CooldownRemaining() == 0 && p.Mana >= slot.ManaCost
 }
 
+// spendCast starts the cooldown of the ability and takes its mana cost.
+func (p *Player) spendCast(abilityType AbilityType) {
+	slot := p.Slot(abilityType)
+	slot.cooldownLeft = slot.EffectiveCooldown()
+	p.Mana -= slot.ManaCost
+	if p.OnCast != nil {
+		p.OnCast(abilityType)
+	}
+}
+
+// Update advances the player's clock by the tick duration in seconds. It counts down the cooldowns,
+// which go by the game time, and regenerates mana. It is called each game tick.
+func (p *Player) Update(delta float64) {
+	p.clock += delta
+	for _, slot := range p.AbilitySlots {
+		slot.cooldownLeft = max(slot.cooldownLeft-delta, 0)
+	}
+	p.RegenerateMana(delta)
+}
+
+// RegenerateMana restores the player's mana over time, up to the maximum.
+// It is called each game tick with the tick duration in seconds.
+func (p *Player) RegenerateMana(delta float64) {
+	p.manaBuffer += (p.ManaRegen + p.talentEffects().ManaRegen) * delta
+	regenerated := int(p.manaBuffer)
+	p.manaBuffer -= float64(regenerated)
+	p.Mana = min(p.Mana+regenerated, p.MaxMana)
+}
+
+// HandleInput moves the player in the direction given by the input.
+// The direction comes from the move actions - the arrow keys give whole steps,
+// and an analog stick pushed part of the way gives a shorter vector that moves the player slower.
+// Without a direction, the player keeps walking to the point picked with the mouse, if there is one.
+// Moving with the keys or the stick cancels the walk.
+func (player *Player) HandleInput(direction [2]float64) {
+	actor := player.Actor
+	if direction[0] == 0 && direction[1] == 0 && player.Walking() {
+		actor.FollowPath()
+		return
+	}
+
+	actor.Path = nil
+	actor.MoveDirectionX = direction[0]
+	actor.MoveDirectionY = direction[1]
+	actor.MoveIn(direction)
+}
+
+// DeathAndDecay places the Death and Decay AoE under the player, if it's off cooldown and there is enough mana.
+// The talents make it bigger and last longer.
 func (p *Player) DeathAndDecay() {
+	if !p.CanCast(DeathAndDecayType) {
+		return
+	}
+	p.spendCast(DeathAndDecayType)
+	talents := p.talentEffects()
+
 	playerBonds := p.Actor.GetBoundingRect()
 	playerCenterX := playerBonds.PositionX + playerBonds.Width/2
 	playerCenterY := playerBonds.PositionY + playerBonds.Height/2
 
-	aoeTexture := rendering.CreateTexture(utils.LoadFile("./assets/circle1.png"))
-
-	aoeActor := actor.NewActor([2]float64{0, 0}, aoeTexture, 4, "Death and Decay", false)
+	aoeActor := actor.NewActor([2]float64{0, 0}, scaledAoETexture(1+talents.AoERadius), 4, "Death and Decay", false)
 	aoeBonds := aoeActor.GetBoundingRect()
 	aoeActor.Position = [2]float64{
 		playerCenterX - aoeBonds.Width/2,
@@ -96,12 +213,13 @@ func (p *Player) DeathAndDecay() {
 
 	DeathAndDecay := &Ability{
 		Actor:     aoeActor,
-		Duration:  3,                 // Duration in seconds for the Death and Decay ability
-		Type:      DeathAndDecayType, // Type of the ability
-		StartTime: time.Now(),        // Time when the ability was activated
+		Duration:  3 + talents.AoEDuration, // Duration in seconds for the Death and Decay ability
+		Type:      DeathAndDecayType,       // Type of the ability
+		StartTime: p.clock,                 // Time when the ability was activated
 	}
 
 	p.Abilities = append(p.Abilities, DeathAndDecay)
+	p.Actor.PlayAction(actor.ClipAttack)
 }
 
 // UpdateAbilitiesDurations updates the durations of the player's abilities.
@@ -110,10 +228,7 @@ func (p *Player) UpdateAbilitiesDurations() {
 	abilitiesCopy := make([]*Ability, 0, len(p.Abilities)) // Pre-allocate for efficiency
 
 	for _, ability := range p.Abilities {
-		if ability.StartTime.IsZero() {
-			continue
-		}
-		timeElapsed := time.Since(ability.StartTime).Seconds()
+		timeElapsed := p.clock - ability.StartTime
 		if timeElapsed >= ability.Duration {
 			continue // Skip abilities that have expired
 		}
@@ -122,9 +237,14 @@ func (p *Player) UpdateAbilitiesDurations() {
 	p.Abilities = abilitiesCopy
 }
 
-// LevelUp increases the player's level and resets health and mana.
-func (p *Player) LevelUp() {
-	p.Level++
-	p.Health = 100 // Reset health to default value
-	p.Mana = 50    // Reset mana to default value
+// TakeDamage reduces the player's health by the given amount, down to 0.
+func (p *Player) TakeDamage(amount int) {
+	if p.God {
+		return
+	}
+	p.Health = max(p.Health-amount, 0)
+	p.Actor.Flash()
+	if p.OnDamaged != nil {
+		p.OnDamaged(amount)
+	}
 }
@@ -0,0 +1,221 @@
+package player
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/utils"
+)
+
+// Talent trees of the heroes, loaded from the data files in assets/talents.
+const (
+	PaladinTalents     = "./assets/talents/paladin.json"
+	DeathKnightTalents = "./assets/talents/deathknight.json"
+)
+
+// TalentEffects are what one rank of a talent changes in the player's abilities. The ranks add up.
+type TalentEffects struct {
+	AoERadius           float64 `json:"aoeRadius"`           // Share of Death and Decay's radius added
+	AoEDuration         float64 `json:"aoeDuration"`         // Seconds added to Death and Decay
+	DeathCoilBounces    int     `json:"deathCoilBounces"`    // NPCs Death Coil jumps to after its target
+	BurstOfLightBounces int     `json:"burstOfLightBounces"` // NPCs Burst of Light jumps to after its target
+	CastRange           float64 `json:"castRange"`           // Distance added to the range of the targeted abilities
+	Cooldown            float64 `json:"cooldown"`            // Share taken off the cooldowns
+	ManaRegen           float64 `json:"manaRegen"`           // Mana per second added to the regeneration
+}
+
+// add sums the effects of the ranks of a talent into the effects.
+func (effects *TalentEffects) add(talent TalentEffects, ranks int) {
+	rank := float64(ranks)
+	effects.AoERadius += talent.AoERadius * rank
+	effects.AoEDuration += talent.AoEDuration * rank
+	effects.DeathCoilBounces += talent.DeathCoilBounces * ranks
+	effects.BurstOfLightBounces += talent.BurstOfLightBounces * ranks
+	effects.CastRange += talent.CastRange * rank
+	effects.Cooldown += talent.Cooldown * rank
+	effects.ManaRegen += talent.ManaRegen * rank
+}
+
+// Talent is a node of a talent tree. Each rank costs a talent point.
+type Talent struct {
+	ID          string        `json:"id"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	MaxRank     int           `json:"maxRank"`
+	Level       int           `json:"level"` // Player level needed to learn the talent
+	Effects     TalentEffects `json:"effects"`
+}
+
+// TalentTree is the list of talents of a hero's path.
+type TalentTree struct {
+	ID      string    `json:"id"` // Key of the tree's choices in the save file
+	Name    string    `json:"name"`
+	Talents []*Talent `json:"talents"`
+}
+
+// LoadTalentTree reads a talent tree from its data file.
+func LoadTalentTree(path string) (*TalentTree, error) {
+	data, err := os.ReadFile(utils.AssetFile(path))
+	if err != nil {
+		return nil, fmt.Errorf("player: %w", err)
+	}
+
+	tree := &TalentTree{}
+	if err := json.Unmarshal(data, tree); err != nil {
+		return nil, fmt.Errorf("player: reading %s: %w", path, err)
+	}
+	for _, talent := range tree.Talents {
+		if talent.ID == "" || talent.MaxRank < 1 {
+			return nil, fmt.Errorf("player: %s: talent %q needs an id and a max rank", path, talent.Name)
+		}
+	}
+	return tree, nil
+}
+
+// Talent returns the talent with the id, or nil if the tree has none.
+func (tree *TalentTree) Talent(id string) *Talent {
+	for _, talent := range tree.Talents {
+		if talent.ID == id {
+			return talent
+		}
+	}
+	return nil
+}
+
+// SetTalentTree gives the player the tree, with no talent learned.
+// The build is the order the talents were learned in a previous game - the player learns them again
+// as the levels give the points, until a different choice replaces the rest of it.
+func (p *Player) SetTalentTree(tree *TalentTree, build []string) {
+	p.TalentTree = tree
+	p.talentRanks = map[string]int{}
+	p.talentBuild = slices.Clone(build)
+	p.learned = 0
+	p.followBuild()
+}
+
+// TalentRank returns the rank the player has in the talent.
+func (p *Player) TalentRank(id string) int {
+	return p.talentRanks[id]
+}
+
+// TalentBuild returns the talents the player learned, in order, one entry per rank.
+func (p *Player) TalentBuild() []string {
+	return p.talentBuild[:p.learned]
+}
+
+// CanLearn reports whether the player can spend a point on the next rank of the talent.
+func (p *Player) CanLearn(talent *Talent) bool {
+	return p.TalentPoints > 0 && p.Level >= talent.Level && p.talentRanks[talent.ID] < talent.MaxRank
+}
+
+// LearnTalent spends a talent point on the next rank of the talent.
+// The choice replaces the rest of the build of the previous game.
+func (p *Player) LearnTalent(id string) error {
+	if p.TalentTree == nil {
+		return errors.New("no talents to learn")
+	}
+	talent := p.TalentTree.Talent(id)
+	if talent == nil {
+		return fmt.Errorf("unknown talent %q", id)
+	}
+	switch {
+	case p.TalentPoints == 0:
+		return errors.New("no talent points left, they come with the levels")
+	case p.Level < talent.Level:
+		return fmt.Errorf("%s needs level %d", talent.Name, talent.Level)
+	case p.talentRanks[talent.ID] >= talent.MaxRank:
+		return fmt.Errorf("%s is at its max rank", talent.Name)
+	}
+	p.talentBuild = append(p.talentBuild[:p.learned], id)
+	p.learn(talent)
+	return nil
+}
+
+// ResetTalents gives the player back the points spent on talents and forgets the build.
+func (p *Player) ResetTalents() {
+	p.TalentPoints += p.learned
+	p.talentRanks = map[string]int{}
+	p.talentBuild = nil
+	p.learned = 0
+	p.applyTalents()
+}
+
+func (p *Player) learn(talent *Talent) {
+	p.TalentPoints--
+	p.talentRanks[talent.ID]++
+	p.learned++
+	p.applyTalents()
+}
+
+// followBuild spends the talent points on the build of the previous game, in order,
+// and stops at the first talent the player can't learn yet.
+func (p *Player) followBuild() {
+	for p.TalentTree != nil && p.learned < len(p.talentBuild) {
+		talent := p.TalentTree.Talent(p.talentBuild[p.learned])
+		if talent == nil || !p.CanLearn(talent) {
+			return
+		}
+		p.learn(talent)
+	}
+}
+
+// talentEffects returns the sum of the effects of the learned talents.
+func (p *Player) talentEffects() TalentEffects {
+	effects := TalentEffects{}
+	if p.TalentTree == nil {
+		return effects
+	}
+	for _, talent := range p.TalentTree.Talents {
+		effects.add(talent.Effects, p.talentRanks[talent.ID])
+	}
+	return effects
+}
+
+// applyTalents updates the cooldowns of the action bar with the learned talents.
+// The other effects are read when the abilities are cast.
+func (p *Player) applyTalents() {
+	reduction := min(p.talentEffects().Cooldown, 0.9)
+	for _, slot := range p.AbilitySlots {
+		slot.reduction = reduction
+	}
+}
+
+// SaveFile holds what the player keeps from game to game.
+type SaveFile struct {
+	Talents map[string][]string `json:"talents"` // Build of each talent tree, by the tree's ID
+}
+
+// LoadSaveFile reads the save file. If the file doesn't exist yet, it returns an empty save.
+func LoadSaveFile(path string) (*SaveFile, error) {
+	save := &SaveFile{Talents: map[string][]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return save, nil
+	}
+	if err != nil {
+		return save, err
+	}
+	if err := json.Unmarshal(data, save); err != nil {
+		return &SaveFile{Talents: map[string][]string{}}, fmt.Errorf("player: reading %s: %w", path, err)
+	}
+	if save.Talents == nil {
+		save.Talents = map[string][]string{}
+	}
+	return save, nil
+}
+
+// Write writes the save file, creating its directory if needed.
+func (save *SaveFile) Write(path string) error {
+	data, err := json.MarshalIndent(save, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,120 @@
+package player
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/utils"
+)
+
+// newTestTree creates a tree of two talents, the second one from level 3.
+func newTestTree() *TalentTree {
+	return &TalentTree{
+		ID:   "test",
+		Name: "Test",
+		Talents: []*Talent{
+			{ID: "range", Name: "Reach", MaxRank: 2, Level: 1, Effects: TalentEffects{CastRange: 25}},
+			{ID: "bounce", Name: "Bounce", MaxRank: 1, Level: 3, Effects: TalentEffects{DeathCoilBounces: 1}},
+		},
+	}
+}
+
+func TestLearnTalent(t *testing.T) {
+	p := newTestPlayer(DefaultXPCurve())
+	p.SetTalentTree(newTestTree(), nil)
+
+	if err := p.LearnTalent("range"); err == nil {
+		t.Error("learned a talent without a talent point")
+	}
+	p.TalentPoints = 3
+	if err := p.LearnTalent("bounce"); err == nil {
+		t.Error("learned a talent below its level")
+	}
+	if err := p.LearnTalent("missing"); err == nil {
+		t.Error("learned a talent the tree doesn't have")
+	}
+	for range 2 {
+		if err := p.LearnTalent("range"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := p.LearnTalent("range"); err == nil {
+		t.Error("learned a talent past its max rank")
+	}
+
+	if p.TalentRank("range") != 2 || p.TalentPoints != 1 {
+		t.Errorf("got rank %d with %d points left, want rank 2 with 1", p.TalentRank("range"), p.TalentPoints)
+	}
+	if got := p.talentEffects().CastRange; got != 50 {
+		t.Errorf("the cast range is raised by %g, want 50", got)
+	}
+}
+
+func TestFollowBuildWithLevels(t *testing.T) {
+	p := newTestPlayer(XPCurve{Base: 1, Growth: 1})
+	p.SetTalentTree(newTestTree(), []string{"range", "bounce", "range"})
+
+	p.GainXP(1)
+	if build := p.TalentBuild(); !slices.Equal(build, []string{"range"}) {
+		t.Errorf("at level 2 the build is %v, want only the first rank of range", build)
+	}
+
+	// bounce needs level 3, and the build goes on from it
+	p.GainXP(2)
+	if build := p.TalentBuild(); !slices.Equal(build, []string{"range", "bounce", "range"}) {
+		t.Errorf("at level 4 the build is %v, want all of it", build)
+	}
+	if p.TalentPoints != 0 {
+		t.Errorf("got %d talent points left, want 0", p.TalentPoints)
+	}
+}
+
+func TestResetTalents(t *testing.T) {
+	p := newTestPlayer(DefaultXPCurve())
+	p.SetTalentTree(newTestTree(), nil)
+	p.TalentPoints = 2
+	p.LearnTalent("range")
+	p.LearnTalent("range")
+
+	p.ResetTalents()
+
+	if p.TalentPoints != 2 || p.TalentRank("range") != 0 || len(p.TalentBuild()) != 0 {
+		t.Errorf("got %d points, rank %d and the build %v, want the 2 points back and nothing learned",
+			p.TalentPoints, p.TalentRank("range"), p.TalentBuild())
+	}
+}
+
+func TestSaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config", "save.json")
+
+	save, err := LoadSaveFile(path)
+	if err != nil || len(save.Talents) != 0 {
+		t.Fatalf("a missing save file gave %v and %v, want an empty save", save.Talents, err)
+	}
+
+	save.Talents["test"] = []string{"range", "bounce"}
+	if err := save.Write(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadSaveFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(loaded.Talents["test"], []string{"range", "bounce"}) {
+		t.Errorf("loaded the build %v, want the saved one", loaded.Talents["test"])
+	}
+}
+
+func TestLoadTalentTree(t *testing.T) {
+	utils.AssetPath = "../assets"
+
+	for _, path := range []string{PaladinTalents, DeathKnightTalents} {
+		if tree, err := LoadTalentTree(path); err != nil || len(tree.Talents) == 0 {
+			t.Errorf("LoadTalentTree(%q) gave %v, want the talents", path, err)
+		}
+	}
+	if tree, err := LoadTalentTree("./assets/talents/missing.json"); err == nil || tree != nil {
+		t.Error("loaded a missing talent tree without an error")
+	}
+}
@@ -0,0 +1,216 @@
+package player
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/actor"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/rendering"
+)
+
+const (
+	castRange       = 350 // Maximum distance to the target of a targeted ability
+	bounceRange     = 200 // Maximum distance a projectile jumps from its target to the next one
+	targetRange     = 500 // The target is dropped once it gets farther than this
+	projectileSpeed = 9
+)
+
+var (
+	deathCoilTexture    *ebiten.Image
+	burstOfLightTexture *ebiten.Image
+)
+
+// loadProjectileTextures makes the Death Coil and Burst of Light projectiles out of the AoE texture,
+// shrunk and tinted green and gold.
+func loadProjectileTextures() {
+	if deathCoilTexture != nil {
+		return
+	}
+	small := rendering.ScaleTexture(loadAoETexture(), 0.25)
+	deathCoilTexture = rendering.TintFrame(small, 0.3, 1, 0.3, 1)
+	burstOfLightTexture = rendering.TintFrame(small, 1, 0.9, 0.3, 1)
+}
+
+// DeathCoilSlot returns the action bar slot of Death Coil, a projectile that kills the target.
+func DeathCoilSlot() *AbilitySlot {
+	loadProjectileTextures()
+	return &AbilitySlot{Type: DeathCoilType, Name: "Death Coil", Action: "CastDeathCoil", Cooldown: 1.5, ManaCost: 15, Icon: deathCoilTexture}
+}
+
+// BurstOfLightSlot returns the action bar slot of Burst of Light, a projectile that cures the target.
+func BurstOfLightSlot() *AbilitySlot {
+	loadProjectileTextures()
+	return &AbilitySlot{Type: BurstOfLightType, Name: "Burst of Light", Action: "CastBurstOfLight", Cooldown: 2, ManaCost: 10, Icon: burstOfLightTexture}
+}
+
+// Walking reports whether the player is walking to a point picked with the mouse.
+func (p *Player) Walking() bool {
+	return len(p.Actor.Path) > 0
+}
+
+// WalkTo makes the player walk through the waypoints, one step each tick.
+// The last waypoint is where the player's top-left corner ends up, like actor.Actor.Path.
+func (p *Player) WalkTo(path [][2]float64) {
+	p.Actor.Path = path
+}
+
+// HasTarget reports whether the player's target can still be hit - it's on the screen and alive.
+func (p *Player) HasTarget() bool {
+	return p.Target != nil && p.Target.Present()
+}
+
+// InRange reports whether the target is close enough for the targeted abilities, further with the talents.
+func (p *Player) InRange(target *actor.Actor) bool {
+	return p.distanceTo(target) <= castRange+p.talentEffects().CastRange
+}
+
+func (p *Player) distanceTo(target *actor.Actor) float64 {
+	return distance(p.Actor, target)
+}
+
+func distance(from, to *actor.Actor) float64 {
+	fromCenter := from.GetBoundingRect().Center()
+	toCenter := to.GetBoundingRect().Center()
+	return math.Hypot(toCenter[0]-fromCenter[0], toCenter[1]-fromCenter[1])
+}
+
+// CycleTarget targets the next NPC in range, nearest first.
+// Without a target it picks the nearest NPC, and after the farthest one it starts over from the nearest.
+// Hidden, dying and leaving NPCs are skipped.
+func (p *Player) CycleTarget(npcs []*actor.Actor) {
+	candidates := make([]*actor.Actor, 0, len(npcs))
+	for _, npc := range npcs {
+		if npc.Present() && p.distanceTo(npc) <= targetRange {
+			candidates = append(candidates, npc)
+		}
+	}
+	if len(candidates) == 0 {
+		p.Target = nil
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return p.distanceTo(candidates[i]) < p.distanceTo(candidates[j])
+	})
+
+	next := 0
+	for i, candidate := range candidates {
+		if candidate == p.Target {
+			next = (i + 1) % len(candidates)
+		}
+	}
+	p.Target = candidates[next]
+}
+
+// UpdateTarget drops the target once it dies, disappears or gets out of range. It is called each game tick.
+func (p *Player) UpdateTarget() {
+	if p.Target != nil && (!p.HasTarget() || p.distanceTo(p.Target) > targetRange) {
+		p.Target = nil
+	}
+}
+
+// Forget drops the actor from the player's target and the projectiles flying to it, once it leaves the game.
+// The entity manager reuses the actor for a later NPC, which the player mustn't hit by mistake.
+func (p *Player) Forget(gone *actor.Actor) {
+	if p.Target == gone {
+		p.Target = nil
+	}
+	p.Abilities = slices.DeleteFunc(p.Abilities, func(ability *Ability) bool {
+		return ability.Target == gone
+	})
+}
+
+// DeathCoil throws a Death Coil at the player's target.
+func (p *Player) DeathCoil() {
+	p.castAtTarget(DeathCoilType, deathCoilTexture)
+}
+
+// BurstOfLight throws a Burst of Light at the player's target.
+func (p *Player) BurstOfLight() {
+	p.castAtTarget(BurstOfLightType, burstOfLightTexture)
+}
+
+// castAtTarget launches a projectile from the player to the target,
+// if there is a target in range, the ability is off cooldown and there is enough mana.
+func (p *Player) castAtTarget(abilityType AbilityType, texture *ebiten.Image) {
+	if !p.HasTarget() || !p.InRange(p.Target) || !p.CanCast(abilityType) {
+		return
+	}
+	p.spendCast(abilityType)
+
+	bounces := p.talentEffects().DeathCoilBounces
+	if abilityType == BurstOfLightType {
+		bounces = p.talentEffects().BurstOfLightBounces
+	}
+	p.launch(abilityType, texture, p.Actor, p.Target, bounces)
+	p.Actor.PlayAction(actor.ClipAttack)
+}
+
+// launch throws a projectile of the ability from one actor to the target.
+func (p *Player) launch(abilityType AbilityType, texture *ebiten.Image, from, target *actor.Actor, bounces int) {
+	projectile := actor.NewActor(from.GetBoundingRect().Center(), texture, projectileSpeed, p.Slot(abilityType).Name, false)
+	p.Abilities = append(p.Abilities, &Ability{
+		Actor:     projectile,
+		Duration:  3, // The projectile fizzles out if it doesn't reach the target in time
+		Type:      abilityType,
+		StartTime: p.clock,
+		Target:    target,
+		Bounces:   bounces,
+	})
+}
+
+// Bounce throws a projectile that has hit its target on to the nearest other NPC in range,
+// if the talents left it bounces to make.
+// The NPCs it has already hit are dying or leaving, so it doesn't go back to them.
+func (p *Player) Bounce(ability *Ability, npcs []*actor.Actor) {
+	if ability.Bounces <= 0 {
+		return
+	}
+	var next *actor.Actor
+	for _, npc := range npcs {
+		if npc == ability.Target || !npc.Present() || distance(ability.Target, npc) > bounceRange {
+			continue
+		}
+		if next == nil || distance(ability.Target, npc) < distance(ability.Target, next) {
+			next = npc
+		}
+	}
+	if next == nil {
+		return
+	}
+	texture := deathCoilTexture
+	if ability.Type == BurstOfLightType {
+		texture = burstOfLightTexture
+	}
+	p.launch(ability.Type, texture, ability.Target, next, ability.Bounces-1)
+}
+
+// UpdateProjectiles moves the projectiles towards their targets and returns the ones that hit.
+// The projectiles that hit, or whose target is gone, are removed from the player's abilities.
+func (p *Player) UpdateProjectiles() []*Ability {
+	var landed []*Ability
+	remaining := make([]*Ability, 0, len(p.Abilities))
+
+	for _, ability := range p.Abilities {
+		if ability.Target == nil {
+			remaining = append(remaining, ability)
+			continue
+		}
+		if !ability.Target.Present() {
+			continue
+		}
+
+		projectileRect := ability.Actor.GetBoundingRect()
+		targetCenter := ability.Target.GetBoundingRect().Center()
+		ability.Actor.MoveTo([2]float64{targetCenter[0] - projectileRect.Width/2, targetCenter[1] - projectileRect.Height/2})
+		if ability.Target.CollidesWithAbility(ability.Actor) {
+			landed = append(landed, ability)
+			continue
+		}
+		remaining = append(remaining, ability)
+	}
+
+	p.Abilities = remaining
+	return landed
+}
@@ -0,0 +1,108 @@
+package rendering
+
+import (
+	"image/color"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// ScreenEffects are the effects applied to the whole frame - the camera shake and the fades to black.
+type ScreenEffects struct {
+	shakeStrength float64 // Pixels the frame moves at most when the shake starts
+	shakeDuration float64
+	shakeLeft     float64
+	offset        [2]float64
+	fade          float64 // Opacity of the black over the frame, 0 to 1
+	fadeSpeed     float64 // Opacity change per second, negative while fading in
+	onBlack       func()  // Runs once the frame is black, see FadeThrough
+}
+
+// Effects are the screen effects of the game.
+var Effects = &ScreenEffects{}
+
+var fadeColor = color.RGBA{0x00, 0x00, 0x00, 0xFF}
+
+// Shake shakes the camera for the duration in seconds, unless a stronger shake is running.
+func (effects *ScreenEffects) Shake(strength, duration float64) {
+	if effects.shakeLeft > 0 && effects.currentShake() > strength {
+		return
+	}
+	effects.shakeStrength = strength
+	effects.shakeDuration = duration
+	effects.shakeLeft = duration
+}
+
+func (effects *ScreenEffects) currentShake() float64 {
+	if effects.shakeLeft <= 0 {
+		return 0
+	}
+	return effects.shakeStrength * effects.shakeLeft / effects.shakeDuration
+}
+
+// ShakeOffset returns how far the camera is moved by the shake in this tick.
+func (effects *ScreenEffects) ShakeOffset() [2]float64 {
+	return effects.offset
+}
+
+// FadeThrough fades the frame to black, runs the change and fades back in.
+func (effects *ScreenEffects) FadeThrough(duration float64, change func()) {
+	if effects.onBlack != nil {
+		return
+	}
+	effects.onBlack = change
+	effects.fadeSpeed = 2 / duration
+}
+
+// FadeIn turns the frame black and fades it in over the duration.
+func (effects *ScreenEffects) FadeIn(duration float64) {
+	if effects.onBlack != nil {
+		return
+	}
+	effects.fade = 1
+	effects.fadeSpeed = -1 / duration
+}
+
+// Fading reports whether a change waits for the frame to turn black.
+func (effects *ScreenEffects) Fading() bool {
+	return effects.onBlack != nil
+}
+
+// Update advances the effects by the tick duration in seconds.
+func (effects *ScreenEffects) Update(delta float64) {
+	effects.shakeLeft = max(effects.shakeLeft-delta, 0)
+	strength := effects.currentShake()
+	effects.offset = [2]float64{(rand.Float64()*2 - 1) * strength, (rand.Float64()*2 - 1) * strength}
+
+	effects.fade += effects.fadeSpeed * delta
+	if effects.fade >= 1 {
+		effects.fade = 1
+		effects.fadeSpeed = -effects.fadeSpeed
+		if change := effects.onBlack; change != nil {
+			effects.onBlack = nil
+			change()
+		}
+	}
+	if effects.fade <= 0 {
+		effects.fade = 0
+		effects.fadeSpeed = 0
+	}
+}
+
+// DrawFade covers the frame with the black of the fade.
+func (effects *ScreenEffects) DrawFade(screen *ebiten.Image) {
+	if effects.fade <= 0 {
+		return
+	}
+	black := fadeColor
+	black.A = uint8(effects.fade * 0xFF)
+	DrawColoredRect(screen, 0, 0, float32(screen.Bounds().Dx()), float32(screen.Bounds().Dy()), black)
+}
+
+// DrawFlashed draws the image blended towards white by the amount, 0 to 1.
+func DrawFlashed(screen, image *ebiten.Image, op *ebiten.DrawImageOptions, amount float64) {
+	var flash colorm.ColorM
+	flash.Translate(amount, amount, amount, 0)
+	colorm.DrawImage(screen, image, flash, &colorm.DrawImageOptions{GeoM: op.GeoM, Blend: op.Blend})
+}
@@ -0,0 +1,147 @@
+package rendering
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Light is a radial light, brightest at its position and fading out at its radius.
+type Light struct {
+	Position [2]float64
+	Radius   float64
+	Color    color.RGBA
+	Flicker  float64           // Share of the light that flickers, e.g. 0.3 for fire, 0 for a steady light
+	Follow   func() [2]float64 // Moves the light each tick, e.g. to stay on an actor, optional
+}
+
+// Lighting darkens the scene to its ambient light and lights it up around the lights.
+type Lighting struct {
+	Ambient  color.RGBA // Light everywhere, e.g. the dark blue of the night
+	Lights   []*Light
+	Fog      *Fog // Hides what's far from the viewer, optional
+	elapsed  float64
+	lightMap *ebiten.Image
+}
+
+// lightImage is a soft white disc, scaled and tinted for each light.
+var lightImage = newParticleImage(128)
+
+// multiplyBlend multiplies the destination by the source color, keeping the destination alpha.
+var multiplyBlend = ebiten.Blend{
+	BlendFactorSourceRGB:        ebiten.BlendFactorZero,
+	BlendFactorSourceAlpha:      ebiten.BlendFactorZero,
+	BlendFactorDestinationRGB:   ebiten.BlendFactorSourceColor,
+	BlendFactorDestinationAlpha: ebiten.BlendFactorOne,
+	BlendOperationRGB:           ebiten.BlendOperationAdd,
+	BlendOperationAlpha:         ebiten.BlendOperationAdd,
+}
+
+// Update moves and flickers the lights, and reveals the fog around the viewer.
+func (lighting *Lighting) Update(delta float64) {
+	lighting.elapsed += delta
+	for _, light := range lighting.Lights {
+		if light.Follow != nil {
+			light.Position = light.Follow()
+		}
+	}
+	if lighting.Fog != nil {
+		lighting.Fog.update()
+	}
+}
+
+// Draw lights the scene and covers it with the fog.
+func (lighting *Lighting) Draw(scene *ebiten.Image) {
+	bounds := scene.Bounds()
+	if lighting.lightMap == nil || lighting.lightMap.Bounds() != bounds {
+		lighting.lightMap = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+	}
+	lighting.lightMap.Fill(lighting.Ambient)
+
+	imageSize := float64(lightImage.Bounds().Dx())
+	for i, light := range lighting.Lights {
+		// Two waves flicker less regularly than one, offset by the index for each fire
+		wave := (math.Sin(lighting.elapsed*11+float64(i)*1.7) + math.Sin(lighting.elapsed*17+float64(i)*2.9)) / 2
+		intensity := float32(1 - light.Flicker*(wave+1)/2)
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(2*light.Radius/imageSize, 2*light.Radius/imageSize)
+		op.GeoM.Translate(light.Position[0]-light.Radius, light.Position[1]-light.Radius)
+		op.ColorScale.ScaleWithColor(light.Color)
+		op.ColorScale.Scale(intensity, intensity, intensity, 1)
+		op.Blend = ebiten.BlendLighter
+		lighting.lightMap.DrawImage(lightImage, op)
+	}
+
+	scene.DrawImage(lighting.lightMap, &ebiten.DrawImageOptions{Blend: multiplyBlend})
+	if lighting.Fog != nil {
+		lighting.Fog.draw(scene)
+	}
+}
+
+// Fog is the fog-of-war, on a coarse grid - the seen areas are dimmed and the rest is hidden.
+type Fog struct {
+	Vision   float64           // Radius of the area seen around the viewer
+	Viewer   func() [2]float64 // Position of the viewer, e.g. the player's center
+	cellSize int
+	columns  int
+	rows     int
+	explored []bool
+	pixels   []byte
+	image    *ebiten.Image
+}
+
+const (
+	fogHiddenAlpha   = 0xF0 // Opacity of the fog over the areas never seen
+	fogExploredAlpha = 0x90 // Opacity of the fog over the areas seen before
+	fogEdge          = 0.3  // Share of the vision radius over which the fog fades in
+)
+
+// NewFog creates a fog covering the whole map, in cells of cellSize pixels.
+func NewFog(width, height, cellSize int, vision float64, viewer func() [2]float64) *Fog {
+	columns := (width + cellSize - 1) / cellSize
+	rows := (height + cellSize - 1) / cellSize
+	return &Fog{
+		Vision:   vision,
+		Viewer:   viewer,
+		cellSize: cellSize,
+		columns:  columns,
+		rows:     rows,
+		explored: make([]bool, columns*rows),
+		pixels:   make([]byte, columns*rows*4),
+		image:    ebiten.NewImage(columns, rows),
+	}
+}
+
+// update reveals the cells around the viewer and writes them to the fog image.
+func (fog *Fog) update() {
+	viewer := fog.Viewer()
+	for row := range fog.rows {
+		for column := range fog.columns {
+			cell := row*fog.columns + column
+			x := (float64(column) + 0.5) * float64(fog.cellSize)
+			y := (float64(row) + 0.5) * float64(fog.cellSize)
+			visibility := (fog.Vision - math.Hypot(x-viewer[0], y-viewer[1])) / (fog.Vision * fogEdge)
+			visibility = min(max(visibility, 0), 1)
+			if visibility > 0 {
+				fog.explored[cell] = true
+			}
+
+			alpha := float64(fogHiddenAlpha)
+			if fog.explored[cell] {
+				alpha = fogExploredAlpha
+			}
+			// Black, so the premultiplied color is all zeros
+			fog.pixels[cell*4+3] = byte(alpha * (1 - visibility))
+		}
+	}
+	fog.image.WritePixels(fog.pixels)
+}
+
+// draw scales the fog image up to the map, blurring the edges of the cells.
+func (fog *Fog) draw(scene *ebiten.Image) {
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(float64(fog.cellSize), float64(fog.cellSize))
+	scene.DrawImage(fog.image, op)
+}
@@ -0,0 +1,220 @@
+package rendering
+
+import (
+	"image/color"
+	"math"
+	"math/rand/v2"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EmitterConfig describes the particles of an emitter.
+type EmitterConfig struct {
+	Rate      float64      `json:"rate"`      // Particles per second while the emitter runs, 0 for a single burst
+	Burst     int          `json:"burst"`     // Particles emitted at once when the emitter starts
+	Duration  float64      `json:"duration"`  // Seconds the emitter runs, 0 until it's stopped
+	Lifetime  [2]float64   `json:"lifetime"`  // Seconds a particle lives, picked between the min and the max
+	Speed     [2]float64   `json:"speed"`     // Pixels per second, picked between the min and the max
+	Direction float64      `json:"direction"` // Angle of the velocity in radians, 0 is right and π/2 is down
+	Spread    float64      `json:"spread"`    // Angle in radians around the direction the velocity is picked in, 2π for all directions
+	Gravity   float64      `json:"gravity"`   // Downward acceleration in pixels per second², negative to rise
+	Area      float64      `json:"area"`      // Radius of the disc around the emitter the particles start in
+	Size      [2]float64   `json:"size"`      // Diameter in pixels at the start and at the end of the life
+	Colors    []color.RGBA `json:"colors"`    // Color over the life, spread evenly from birth to death, alpha-premultiplied
+	Additive  bool         `json:"additive"`  // Adds the particles to what's under them, for glows and fire
+	Layer     Layer        `json:"layer"`     // Layer the particles are drawn on, see ParticleSystem.Submit
+}
+
+// Emitter emits the particles of its config from its position.
+type Emitter struct {
+	Config   *EmitterConfig
+	Position [2]float64
+	Follow   func() [2]float64 // Moves the emitter each tick, e.g. to stay on an actor, optional
+	elapsed  float64
+	pending  float64 // Particles due but not emitted yet, as the rate doesn't give a whole number per tick
+	stopped  bool
+}
+
+// Stop stops the emission. The emitted particles live on until their lifetime ends.
+func (emitter *Emitter) Stop() {
+	emitter.stopped = true
+}
+
+type particle struct {
+	config   *EmitterConfig
+	position [2]float64
+	velocity [2]float64
+	age      float64
+	lifetime float64
+}
+
+// ParticleSystem updates and draws the particles of its emitters, from a pool allocated once.
+type ParticleSystem struct {
+	particles []particle // The live particles are particles[:live]
+	live      int
+	emitters  []*Emitter
+}
+
+// NewParticleSystem creates a particle system that holds up to capacity particles at a time.
+func NewParticleSystem(capacity int) *ParticleSystem {
+	return &ParticleSystem{particles: make([]particle, capacity)}
+}
+
+// Emit starts an emitter at the position.
+func (system *ParticleSystem) Emit(config *EmitterConfig, position [2]float64) *Emitter {
+	emitter := &Emitter{Config: config, Position: position}
+	system.spawn(emitter, config.Burst)
+	if config.Rate == 0 {
+		emitter.stopped = true
+	} else {
+		system.emitters = append(system.emitters, emitter)
+	}
+	return emitter
+}
+
+// Update runs the emitters and moves the particles by the tick duration in seconds.
+func (system *ParticleSystem) Update(delta float64) {
+	emitters := system.emitters[:0]
+	for _, emitter := range system.emitters {
+		if emitter.Follow != nil {
+			emitter.Position = emitter.Follow()
+		}
+		emitter.elapsed += delta
+		if emitter.Config.Duration > 0 && emitter.elapsed >= emitter.Config.Duration {
+			emitter.stopped = true
+		}
+		if emitter.stopped {
+			continue
+		}
+		emitter.pending += emitter.Config.Rate * delta
+		count := int(emitter.pending)
+		emitter.pending -= float64(count)
+		system.spawn(emitter, count)
+		emitters = append(emitters, emitter)
+	}
+	system.emitters = emitters
+
+	for i := 0; i < system.live; {
+		p := &system.particles[i]
+		p.age += delta
+		if p.age >= p.lifetime {
+			// Swap the dead particle with the last live one
+			system.live--
+			system.particles[i] = system.particles[system.live]
+			continue
+		}
+		p.velocity[1] += p.config.Gravity * delta
+		p.position[0] += p.velocity[0] * delta
+		p.position[1] += p.velocity[1] * delta
+		i++
+	}
+}
+
+// Clear removes all the emitters and particles.
+func (system *ParticleSystem) Clear() {
+	system.emitters = nil
+	system.live = 0
+}
+
+func (system *ParticleSystem) spawn(emitter *Emitter, count int) {
+	config := emitter.Config
+	for range count {
+		if system.live == len(system.particles) {
+			return
+		}
+		angle := config.Direction + (rand.Float64()-0.5)*config.Spread
+		speed := between(config.Speed)
+		offsetAngle := rand.Float64() * 2 * math.Pi
+		// The square root spreads the particles evenly over the disc
+		offset := math.Sqrt(rand.Float64()) * config.Area
+
+		system.particles[system.live] = particle{
+			config: config,
+			position: [2]float64{
+				emitter.Position[0] + math.Cos(offsetAngle)*offset,
+				emitter.Position[1] + math.Sin(offsetAngle)*offset,
+			},
+			velocity: [2]float64{math.Cos(angle) * speed, math.Sin(angle) * speed},
+			lifetime: between(config.Lifetime),
+		}
+		system.live++
+	}
+}
+
+func between(limits [2]float64) float64 {
+	return limits[0] + rand.Float64()*(limits[1]-limits[0])
+}
+
+// particleImage is a soft white dot, scaled and tinted for each particle.
+var particleImage = newParticleImage(16)
+
+func newParticleImage(size int) *ebiten.Image {
+	image := ebiten.NewImage(size, size)
+	pixels := make([]byte, size*size*4)
+	radius := float64(size) / 2
+	for y := range size {
+		for x := range size {
+			distance := math.Hypot(float64(x)+0.5-radius, float64(y)+0.5-radius) / radius
+			alpha := byte(max(0, 1-distance) * max(0, 1-distance) * 0xFF)
+			// Premultiplied alpha
+			i := (y*size + x) * 4
+			pixels[i], pixels[i+1], pixels[i+2], pixels[i+3] = alpha, alpha, alpha, alpha
+		}
+	}
+	image.WritePixels(pixels)
+	return image
+}
+
+// Submit queues the particles on the layers of their emitters.
+func (system *ParticleSystem) Submit(queue *RenderQueue) {
+	var used [layerCount]bool
+	for i := range system.live {
+		used[system.particles[i].config.Layer] = true
+	}
+	for layer, ok := range used {
+		if ok {
+			queue.Submit(Layer(layer), 0, func(screen *ebiten.Image) { system.Draw(screen, Layer(layer)) })
+		}
+	}
+}
+
+// Draw draws the live particles of the layer, with their size and color at their age.
+func (system *ParticleSystem) Draw(screen *ebiten.Image, layer Layer) {
+	imageSize := float64(particleImage.Bounds().Dx())
+	for i := range system.live {
+		p := &system.particles[i]
+		if p.config.Layer != layer {
+			continue
+		}
+		life := p.age / p.lifetime
+		size := p.config.Size[0] + (p.config.Size[1]-p.config.Size[0])*life
+		if size <= 0 {
+			continue
+		}
+
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(size/imageSize, size/imageSize)
+		op.GeoM.Translate(p.position[0]-size/2, p.position[1]-size/2)
+		op.ColorScale.ScaleWithColor(colorAt(p.config.Colors, life))
+		if p.config.Additive {
+			op.Blend = ebiten.BlendLighter
+		}
+		screen.DrawImage(particleImage, op)
+	}
+}
+
+// colorAt returns the color at the point of the life, 0 to 1.
+func colorAt(colors []color.RGBA, life float64) color.Color {
+	if len(colors) == 0 {
+		return color.White
+	}
+	if len(colors) == 1 {
+		return colors[0]
+	}
+	position := life * float64(len(colors)-1)
+	index := min(int(position), len(colors)-2)
+	weight := position - float64(index)
+	from, to := colors[index], colors[index+1]
+	blend := func(a, b uint8) uint8 { return uint8(float64(a) + (float64(b)-float64(a))*weight) }
+	return color.RGBA{blend(from.R, to.R), blend(from.G, to.G), blend(from.B, to.B), blend(from.A, to.A)}
+}
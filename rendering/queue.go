@@ -0,0 +1,100 @@
+package rendering
+
+import (
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Layer is a step of the frame. The layers are drawn in the order they are declared.
+type Layer int
+
+const (
+	LayerGround        Layer = iota // The map - terrain, obstacles and zones
+	LayerGroundEffects              // Effects painted on the ground - AoEs, telegraphs and the selection ring
+	LayerActors                     // The player, the NPCs and the other characters, sorted by their feet
+	LayerProjectiles                // Everything flying above the actors
+	LayerOverhead                   // UI attached to the actors, like the nameplates
+	LayerHUD                        // UI attached to the screen
+	layerCount
+)
+
+var layerNames = [layerCount]string{"ground", "ground-effects", "actors", "projectiles", "overhead", "hud"}
+
+// String returns the name of the layer, e.g. "ground-effects".
+func (layer Layer) String() string {
+	if layer < 0 || layer >= layerCount {
+		return "unknown"
+	}
+	return layerNames[layer]
+}
+
+type drawable struct {
+	depth float64
+	draw  func(screen *ebiten.Image)
+}
+
+// RenderQueue collects what is drawn in a frame by layer. The actors layer is sorted by depth.
+type RenderQueue struct {
+	Offset   [2]float64 // Moves everything but the HUD, e.g. for the camera shake
+	Lighting *Lighting  // Lights the layers under the overhead UI, nil for no lighting
+	layers   [layerCount][]drawable
+	scene    *ebiten.Image // The layers moved by the offset or lit, drawn offscreen first
+}
+
+// Submit queues a draw call on the layer. The depth only sorts the actors layer.
+func (queue *RenderQueue) Submit(layer Layer, depth float64, draw func(screen *ebiten.Image)) {
+	queue.layers[layer] = append(queue.layers[layer], drawable{depth: depth, draw: draw})
+}
+
+// SubmitImage queues an image drawn with the options.
+func (queue *RenderQueue) SubmitImage(layer Layer, depth float64, image *ebiten.Image, op *ebiten.DrawImageOptions) {
+	queue.Submit(layer, depth, func(screen *ebiten.Image) { screen.DrawImage(image, op) })
+}
+
+// Flush draws the queued calls and empties the queue.
+func (queue *RenderQueue) Flush(screen *ebiten.Image) {
+	actors := queue.layers[LayerActors]
+	sort.SliceStable(actors, func(i, j int) bool { return actors[i].depth < actors[j].depth })
+
+	world := screen
+	if queue.Offset != [2]float64{} || queue.Lighting != nil {
+		world = queue.sceneFor(screen)
+	}
+	for layer := range queue.layers {
+		target := world
+		if Layer(layer) == LayerOverhead && queue.Lighting != nil {
+			// The nameplates stay readable in the dark
+			queue.Lighting.Draw(world)
+		}
+		if Layer(layer) == LayerHUD {
+			target = screen
+			queue.drawScene(screen, world)
+		}
+		for _, queued := range queue.layers[layer] {
+			queued.draw(target)
+		}
+		// Keep the memory for the next frame, but not the draw calls
+		clear(queue.layers[layer])
+		queue.layers[layer] = queue.layers[layer][:0]
+	}
+}
+
+// sceneFor returns the cleared offscreen image the size of the screen.
+func (queue *RenderQueue) sceneFor(screen *ebiten.Image) *ebiten.Image {
+	if queue.scene == nil || queue.scene.Bounds() != screen.Bounds() {
+		queue.scene = ebiten.NewImage(screen.Bounds().Dx(), screen.Bounds().Dy())
+	}
+	queue.scene.Clear()
+	return queue.scene
+}
+
+// drawScene draws the offscreen layers on the screen, moved by the offset.
+func (queue *RenderQueue) drawScene(screen, world *ebiten.Image) {
+	if world == screen {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(queue.Offset[0], queue.Offset[1])
+	screen.DrawImage(world, op)
+}
@@ -6,6 +6,7 @@ import (
 	"image/color"
 	_ "image/png"
 	"log"
+	"math"
 	"os"
 
 	"github.com/hajimehoshi/ebiten/v2"
@@ -17,6 +18,9 @@ import (
 
 var (
 	faceSource *text.GoTextFaceSource
+	whiteImage = ebiten.NewImage(3, 3)
+	// whiteSubImage is the source for filling vector paths, the inner pixel avoids bleeding at the edges
+	whiteSubImage = whiteImage.SubImage(image.Rect(1, 1, 2, 2)).(*ebiten.Image)
 )
 
 const (
@@ -31,17 +35,12 @@ func init() {
 		log.Fatal(err)
 	}
 	faceSource = s
+	whiteImage.Fill(color.White)
 }
 
 // Rendering utils --- module?
-func DrawImageWithMatrix(screen *ebiten.Image, image *ebiten.Image, transformationM *ebiten.DrawImageOptions, debug bool) {
+func DrawImageWithMatrix(screen *ebiten.Image, image *ebiten.Image, transformationM *ebiten.DrawImageOptions) {
 	screen.DrawImage(image, transformationM)
-
-	if debug {
-		imgWidth, imgHeight := image.Size()
-		DrawBorder(image, 0, 0, float32(imgWidth), float32(imgHeight))
-	}
-
 }
 
 func DrawBorder(screen *ebiten.Image, x, y, width, height float32) {
@@ -62,6 +61,78 @@ func CreateTexture(imageFile *os.File) *ebiten.Image {
 	return ebiten.NewImageFromImage(img)
 }
 
+// LoadTexture decodes the image file, and returns the error instead of exiting.
+func LoadTexture(path string) (*ebiten.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// MissingTexture returns a magenta square, drawn in place of a missing texture.
+func MissingTexture() *ebiten.Image {
+	missing := ebiten.NewImage(32, 32)
+	missing.Fill(color.RGBA{0xFF, 0x00, 0xFF, 0xFF})
+	return missing
+}
+
+// ScaleTexture returns a copy of the image scaled by the given factor.
+func ScaleTexture(image *ebiten.Image, scale float64) *ebiten.Image {
+	bounds := image.Bounds()
+	scaled := ebiten.NewImage(int(float64(bounds.Dx())*scale), int(float64(bounds.Dy())*scale))
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.Filter = ebiten.FilterLinear
+	scaled.DrawImage(image, op)
+
+	return scaled
+}
+
+// FlipHorizontal returns a mirrored copy of the image.
+func FlipHorizontal(image *ebiten.Image) *ebiten.Image {
+	bounds := image.Bounds()
+	flipped := ebiten.NewImage(bounds.Dx(), bounds.Dy())
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(-1, 1)
+	op.GeoM.Translate(float64(bounds.Dx()), 0)
+	flipped.DrawImage(image, op)
+
+	return flipped
+}
+
+// OffsetFrame returns a copy of the image moved by the given offset within the same bounds.
+func OffsetFrame(image *ebiten.Image, offsetX, offsetY float64) *ebiten.Image {
+	bounds := image.Bounds()
+	frame := ebiten.NewImage(bounds.Dx(), bounds.Dy())
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(offsetX, offsetY)
+	frame.DrawImage(image, op)
+
+	return frame
+}
+
+// TintFrame returns a copy of the image with its colors scaled.
+func TintFrame(image *ebiten.Image, red, green, blue, alpha float32) *ebiten.Image {
+	bounds := image.Bounds()
+	frame := ebiten.NewImage(bounds.Dx(), bounds.Dy())
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.Scale(red, green, blue, alpha)
+	frame.DrawImage(image, op)
+
+	return frame
+}
+
 func DrawBox(screen *ebiten.Image, x, y, width, height float32) {
 	bgColor := color.RGBA{0xFF, 0x00, 0x00, 0xFF} // Red background (like background-color)
 	borderColor := color.RGBA{255, 0, 0, 255}
@@ -115,3 +186,79 @@ func DrawCircle(screen *ebiten.Image, x, y, radius float32) {
 	// Draw a stroked circle (border)
 	vector.StrokeCircle(screen, x, y, radius, 2.0, color.RGBA{0xFF, 0x00, 0x00, 0xFF}, false)
 }
+
+// DrawSelectionRing draws the ring that marks the selected target.
+func DrawSelectionRing(screen *ebiten.Image, x, y, radius float32) {
+	vector.StrokeCircle(screen, x, y, radius, 2, selectionRingColor, true)
+}
+
+var selectionRingColor = color.RGBA{0xFF, 0xD7, 0x00, 0xFF}
+
+// DrawOutline draws a one pixel wide rectangle outline.
+func DrawOutline(screen *ebiten.Image, x, y, width, height float32, strokeColor color.Color) {
+	vector.StrokeRect(screen, x, y, width, height, 1, strokeColor, false)
+}
+
+// DrawCircleOutline draws a one pixel wide circle outline.
+func DrawCircleOutline(screen *ebiten.Image, x, y, radius float32, strokeColor color.Color) {
+	vector.StrokeCircle(screen, x, y, radius, 1, strokeColor, true)
+}
+
+// DrawLine draws a one pixel wide line between two points.
+func DrawLine(screen *ebiten.Image, x1, y1, x2, y2 float32, strokeColor color.Color) {
+	vector.StrokeLine(screen, x1, y1, x2, y2, 1, strokeColor, true)
+}
+
+// DrawColoredCircle draws a filled circle with the given color and no border.
+func DrawColoredCircle(screen *ebiten.Image, x, y, radius float32, fillColor color.Color) {
+	vector.DrawFilledCircle(screen, x, y, radius, fillColor, false)
+}
+
+// DrawColoredRect draws a filled rectangle with the given color and no border.
+func DrawColoredRect(screen *ebiten.Image, x, y, width, height float32, fillColor color.Color) {
+	vector.DrawFilledRect(screen, x, y, width, height, fillColor, false)
+}
+
+// DrawProgressBar draws a bar filled up to the given progress (0 to 1) over a dark background.
+func DrawProgressBar(screen *ebiten.Image, x, y, width, height float32, progress float64, fillColor color.Color) {
+	progress = max(0, min(progress, 1))
+	vector.DrawFilledRect(screen, x, y, width, height, color.RGBA{0x20, 0x20, 0x20, 0xC0}, false)
+	vector.DrawFilledRect(screen, x, y, width*float32(progress), height, fillColor, false)
+	vector.StrokeRect(screen, x, y, width, height, 1, color.RGBA{0x00, 0x00, 0x00, 0xFF}, false)
+}
+
+// DrawPie draws a filled circle sector from startAngle to endAngle, in radians.
+func DrawPie(screen *ebiten.Image, x, y, radius, startAngle, endAngle float32, fillColor color.Color) {
+	var path vector.Path
+	path.MoveTo(x, y)
+	path.Arc(x, y, radius, startAngle, endAngle, vector.Clockwise)
+	path.Close()
+
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	r, g, b, a := fillColor.RGBA()
+	for i := range vertices {
+		vertices[i].SrcX = 1
+		vertices[i].SrcY = 1
+		vertices[i].ColorR = float32(r) / 0xffff
+		vertices[i].ColorG = float32(g) / 0xffff
+		vertices[i].ColorB = float32(b) / 0xffff
+		vertices[i].ColorA = float32(a) / 0xffff
+	}
+
+	op := &ebiten.DrawTrianglesOptions{}
+	op.ColorScaleMode = ebiten.ColorScaleModePremultipliedAlpha
+	op.AntiAlias = true
+	screen.DrawTriangles(vertices, indices, whiteSubImage, op)
+}
+
+// DrawCooldownSweep darkens the remaining share (1 to 0) of a square button's cooldown.
+func DrawCooldownSweep(screen *ebiten.Image, x, y, size float32, remaining float64) {
+	if remaining <= 0 {
+		return
+	}
+	button := screen.SubImage(image.Rect(int(x), int(y), int(x+size), int(y+size))).(*ebiten.Image)
+
+	// The sub-image clips the sweep circle to the square
+	start := float32(-math.Pi/2 + 2*math.Pi*(1-min(remaining, 1)))
+	DrawPie(button, x+size/2, y+size/2, size, start, float32(3*math.Pi/2), color.RGBA{0x00, 0x00, 0x00, 0xB0})
+}
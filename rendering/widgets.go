@@ -0,0 +1,608 @@
+package rendering
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Theme holds the colors and the font size used by the widgets.
+type Theme struct {
+	FontSize    float64
+	TextColor   color.Color
+	PanelColor  color.Color
+	BorderColor color.Color
+	ButtonColor color.Color
+	FocusColor  color.Color // Background of the focused button and of the selected list item
+	BarColor    color.Color
+	BorderWidth float32
+}
+
+// DefaultTheme is the dark red theme of the game menus.
+var DefaultTheme = &Theme{
+	FontSize:    FontSize,
+	TextColor:   color.White,
+	PanelColor:  color.RGBA{0x20, 0x08, 0x08, 0xE0},
+	BorderColor: color.RGBA{0xA0, 0x00, 0x00, 0xFF},
+	ButtonColor: color.RGBA{0x50, 0x10, 0x10, 0xFF},
+	FocusColor:  color.RGBA{0xC0, 0x20, 0x20, 0xFF},
+	BarColor:    color.RGBA{0xC0, 0x20, 0x20, 0xFF},
+	BorderWidth: 2,
+}
+
+func (theme *Theme) face() *text.GoTextFace {
+	return &text.GoTextFace{Source: faceSource, Size: theme.FontSize}
+}
+
+// measureText returns the size of the text in the theme's font.
+func (theme *Theme) measureText(textToMeasure string) (float64, float64) {
+	return text.Measure(textToMeasure, theme.face(), theme.FontSize*1.5)
+}
+
+// drawText draws the text in the theme's font and color in the rectangle.
+func (theme *Theme) drawText(screen *ebiten.Image, textToDraw string, rect Rect, centered bool) {
+	op := &text.DrawOptions{}
+	op.ColorScale.ScaleWithColor(theme.TextColor)
+	op.LineSpacing = theme.FontSize * 1.5
+	op.LayoutOptions.SecondaryAlign = text.AlignCenter
+	if centered {
+		op.LayoutOptions.PrimaryAlign = text.AlignCenter
+		op.GeoM.Translate(rect.X+rect.Width/2, rect.Y+rect.Height/2)
+	} else {
+		op.GeoM.Translate(rect.X, rect.Y+rect.Height/2)
+	}
+	text.Draw(screen, textToDraw, theme.face(), op)
+}
+
+// Rect is the area a widget is laid out in.
+type Rect struct {
+	X, Y, Width, Height float64
+}
+
+// Contains reports whether the point is inside the rectangle.
+func (rect Rect) Contains(x, y float64) bool {
+	return x >= rect.X && x < rect.X+rect.Width && y >= rect.Y && y < rect.Y+rect.Height
+}
+
+// Anchor is the point of the screen a panel is attached to.
+type Anchor int
+
+const (
+	AnchorCenter Anchor = iota
+	AnchorTop
+	AnchorBottom
+	AnchorLeft
+	AnchorRight
+	AnchorTopLeft
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomRight
+	AnchorPoint // Centered above UI.Point, for dialogs attached to something on the screen
+)
+
+// Widget is an element of the retained-mode UI.
+type Widget interface {
+	Measure(theme *Theme) (width, height float64)
+	Layout(rect Rect)
+	Bounds() Rect
+	Draw(screen *ebiten.Image, theme *Theme, focused bool)
+}
+
+// Focusable is a widget that takes keyboard focus and reacts to keys and clicks.
+type Focusable interface {
+	Widget
+	// HandleKey reacts to a key pressed while the widget has the focus, and returns false if it wasn't used.
+	HandleKey(key ebiten.Key) bool
+	// Click reacts to a mouse click at the given point, inside the widget's bounds.
+	Click(x, y float64)
+}
+
+// Label is a line of text.
+type Label struct {
+	Text     string
+	Centered bool
+	rect     Rect
+}
+
+func (label *Label) Measure(theme *Theme) (float64, float64) {
+	return theme.measureText(label.Text)
+}
+
+func (label *Label) Layout(rect Rect) { label.rect = rect }
+func (label *Label) Bounds() Rect     { return label.rect }
+
+func (label *Label) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	theme.drawText(screen, label.Text, label.rect, label.Centered)
+}
+
+// Button is a focusable text button. Enter, Space or a click runs OnClick.
+type Button struct {
+	Text    string
+	OnClick func()
+	rect    Rect
+}
+
+func (button *Button) Measure(theme *Theme) (float64, float64) {
+	width, height := theme.measureText(button.Text)
+	return width + 2*theme.FontSize, height + theme.FontSize
+}
+
+func (button *Button) Layout(rect Rect) { button.rect = rect }
+func (button *Button) Bounds() Rect     { return button.rect }
+
+func (button *Button) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	rect := button.rect
+	background := theme.ButtonColor
+	if focused {
+		background = theme.FocusColor
+	}
+	vector.DrawFilledRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), background, false)
+	vector.StrokeRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), theme.BorderWidth, theme.BorderColor, false)
+	theme.drawText(screen, button.Text, rect, true)
+}
+
+func (button *Button) HandleKey(key ebiten.Key) bool {
+	if key == ebiten.KeyEnter || key == ebiten.KeySpace {
+		button.activate()
+		return true
+	}
+	return false
+}
+
+func (button *Button) Click(x, y float64) { button.activate() }
+
+func (button *Button) activate() {
+	if button.OnClick != nil {
+		button.OnClick()
+	}
+}
+
+// ProgressBar is a horizontal bar filled up to Value (0 to 1).
+type ProgressBar struct {
+	Value  float64
+	Height float64
+	Color  color.Color // Fill color, the theme's bar color if nil
+	rect   Rect
+}
+
+func (bar *ProgressBar) Measure(theme *Theme) (float64, float64) {
+	height := bar.Height
+	if height == 0 {
+		height = theme.FontSize
+	}
+	return 0, height
+}
+
+func (bar *ProgressBar) Layout(rect Rect) { bar.rect = rect }
+func (bar *ProgressBar) Bounds() Rect     { return bar.rect }
+
+func (bar *ProgressBar) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	fillColor := bar.Color
+	if fillColor == nil {
+		fillColor = theme.BarColor
+	}
+	rect := bar.rect
+	DrawProgressBar(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), bar.Value, fillColor)
+}
+
+// Slider is a focusable value from 0 to 1. Left and Right change the value by Step.
+type Slider struct {
+	Text     string
+	Value    float64
+	Step     float64             // Change of the value on Left and Right, 0.1 if 0
+	OnChange func(value float64) // Called when the value changes, optional
+	rect     Rect
+}
+
+func (slider *Slider) Measure(theme *Theme) (float64, float64) {
+	width, height := theme.measureText(slider.Text + " 100%")
+	return width + 2*theme.FontSize, height + theme.FontSize
+}
+
+func (slider *Slider) Layout(rect Rect) { slider.rect = rect }
+func (slider *Slider) Bounds() Rect     { return slider.rect }
+
+func (slider *Slider) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	rect := slider.rect
+	background := theme.ButtonColor
+	if focused {
+		background = theme.FocusColor
+	}
+	vector.DrawFilledRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), background, false)
+	vector.DrawFilledRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width*slider.Value), float32(rect.Height), theme.BarColor, false)
+	vector.StrokeRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), theme.BorderWidth, theme.BorderColor, false)
+	theme.drawText(screen, fmt.Sprintf("%s %d%%", slider.Text, int(math.Round(slider.Value*100))), rect, true)
+}
+
+func (slider *Slider) HandleKey(key ebiten.Key) bool {
+	step := slider.Step
+	if step == 0 {
+		step = 0.1
+	}
+	switch key {
+	case ebiten.KeyArrowLeft:
+		slider.Set(slider.Value - step)
+	case ebiten.KeyArrowRight:
+		slider.Set(slider.Value + step)
+	default:
+		return false
+	}
+	return true
+}
+
+func (slider *Slider) Click(x, y float64) {
+	slider.Set((x - slider.rect.X) / slider.rect.Width)
+}
+
+// Set changes the value and calls OnChange.
+func (slider *Slider) Set(value float64) {
+	slider.Value = math.Round(max(0, min(value, 1))*100) / 100
+	if slider.OnChange != nil {
+		slider.OnChange(slider.Value)
+	}
+}
+
+// Picture is an image, scaled to fit its size without changing its aspect ratio.
+type Picture struct {
+	Image         *ebiten.Image
+	Width, Height float64 // Size of the picture, the image's size if 0
+	rect          Rect
+}
+
+func (picture *Picture) Measure(theme *Theme) (float64, float64) {
+	if picture.Image == nil {
+		return picture.Width, picture.Height
+	}
+	width, height := picture.Width, picture.Height
+	if width == 0 || height == 0 {
+		bounds := picture.Image.Bounds()
+		width, height = float64(bounds.Dx()), float64(bounds.Dy())
+	}
+	return width, height
+}
+
+func (picture *Picture) Layout(rect Rect) { picture.rect = rect }
+func (picture *Picture) Bounds() Rect     { return picture.rect }
+
+// Draw draws the image centered in the picture's area.
+func (picture *Picture) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	if picture.Image == nil {
+		return
+	}
+	width, height := picture.Measure(theme)
+	bounds := picture.Image.Bounds()
+	scale := min(width/float64(bounds.Dx()), height/float64(bounds.Dy()))
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(scale, scale)
+	op.GeoM.Translate(
+		picture.rect.X+(picture.rect.Width-float64(bounds.Dx())*scale)/2,
+		picture.rect.Y+(picture.rect.Height-float64(bounds.Dy())*scale)/2,
+	)
+	screen.DrawImage(picture.Image, op)
+}
+
+// List is a focusable list of items with one selected item. Enter runs OnActivate.
+type List struct {
+	Items      []string
+	Selected   int
+	OnSelect   func(index int) // Called when the selection changes, optional
+	OnActivate func(index int) // Called on Enter or on a click on the selected item, optional
+	rect       Rect
+}
+
+func (list *List) itemHeight(theme *Theme) float64 {
+	return theme.FontSize * 2
+}
+
+func (list *List) Measure(theme *Theme) (float64, float64) {
+	width := 0.0
+	for _, item := range list.Items {
+		itemWidth, _ := theme.measureText(item)
+		width = max(width, itemWidth)
+	}
+	return width + 2*theme.FontSize, float64(len(list.Items)) * list.itemHeight(theme)
+}
+
+func (list *List) Layout(rect Rect) { list.rect = rect }
+func (list *List) Bounds() Rect     { return list.rect }
+
+func (list *List) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	itemHeight := list.rect.Height / float64(max(len(list.Items), 1))
+	for i, item := range list.Items {
+		itemRect := Rect{X: list.rect.X, Y: list.rect.Y + float64(i)*itemHeight, Width: list.rect.Width, Height: itemHeight}
+		if i == list.Selected {
+			selectedColor := theme.ButtonColor
+			if focused {
+				selectedColor = theme.FocusColor
+			}
+			vector.DrawFilledRect(screen, float32(itemRect.X), float32(itemRect.Y), float32(itemRect.Width), float32(itemRect.Height), selectedColor, false)
+		}
+		theme.drawText(screen, item, itemRect, true)
+	}
+}
+
+func (list *List) HandleKey(key ebiten.Key) bool {
+	switch key {
+	case ebiten.KeyArrowUp:
+		if list.Selected == 0 {
+			return false
+		}
+		list.Select(list.Selected - 1)
+	case ebiten.KeyArrowDown:
+		if list.Selected == len(list.Items)-1 {
+			return false
+		}
+		list.Select(list.Selected + 1)
+	case ebiten.KeyEnter:
+		if list.OnActivate != nil {
+			list.OnActivate(list.Selected)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (list *List) Click(x, y float64) {
+	if len(list.Items) == 0 {
+		return
+	}
+	index := int((y - list.rect.Y) / (list.rect.Height / float64(len(list.Items))))
+	index = max(0, min(index, len(list.Items)-1))
+	if index == list.Selected && list.OnActivate != nil {
+		list.OnActivate(index)
+		return
+	}
+	list.Select(index)
+}
+
+// Select changes the selected item and calls OnSelect.
+func (list *List) Select(index int) {
+	list.Selected = index
+	if list.OnSelect != nil {
+		list.OnSelect(index)
+	}
+}
+
+// Panel stacks its children vertically, stretched to the width of the widest one.
+type Panel struct {
+	Children   []Widget
+	Padding    float64
+	Spacing    float64
+	MinWidth   float64
+	Background bool // Draw the panel's background and border, or only its children
+	rect       Rect
+}
+
+// NewPanel creates a panel with a background and the default padding and spacing.
+func NewPanel(children ...Widget) *Panel {
+	return &Panel{Children: children, Padding: 16, Spacing: 8, Background: true}
+}
+
+func (panel *Panel) Measure(theme *Theme) (float64, float64) {
+	width := panel.MinWidth - 2*panel.Padding
+	height := 0.0
+	for i, child := range panel.Children {
+		childWidth, childHeight := child.Measure(theme)
+		width = max(width, childWidth)
+		height += childHeight
+		if i > 0 {
+			height += panel.Spacing
+		}
+	}
+	return width + 2*panel.Padding, height + 2*panel.Padding
+}
+
+// Layout places the panel in the rectangle, see LayoutWithTheme.
+func (panel *Panel) Layout(rect Rect) { panel.rect = rect }
+func (panel *Panel) Bounds() Rect     { return panel.rect }
+
+// LayoutWithTheme places the panel in the rectangle and stacks its children inside it.
+func (panel *Panel) LayoutWithTheme(rect Rect, theme *Theme) {
+	panel.rect = rect
+	y := rect.Y + panel.Padding
+	for _, child := range panel.Children {
+		_, childHeight := child.Measure(theme)
+		childRect := Rect{X: rect.X + panel.Padding, Y: y, Width: rect.Width - 2*panel.Padding, Height: childHeight}
+		if childPanel, ok := child.(*Panel); ok {
+			childPanel.LayoutWithTheme(childRect, theme)
+		} else {
+			child.Layout(childRect)
+		}
+		y += childHeight + panel.Spacing
+	}
+}
+
+func (panel *Panel) Draw(screen *ebiten.Image, theme *Theme, focused bool) {
+	panel.drawWithFocus(screen, theme, nil)
+}
+
+func (panel *Panel) drawWithFocus(screen *ebiten.Image, theme *Theme, focus Focusable) {
+	rect := panel.rect
+	if panel.Background {
+		vector.DrawFilledRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), theme.PanelColor, false)
+		vector.StrokeRect(screen, float32(rect.X), float32(rect.Y), float32(rect.Width), float32(rect.Height), theme.BorderWidth, theme.BorderColor, false)
+	}
+	for _, child := range panel.Children {
+		if childPanel, ok := child.(*Panel); ok {
+			childPanel.drawWithFocus(screen, theme, focus)
+			continue
+		}
+		focusable, ok := child.(Focusable)
+		child.Draw(screen, theme, ok && focusable == focus)
+	}
+}
+
+// focusables returns the focusable widgets of the panel and its sub-panels, in layout order.
+func (panel *Panel) focusables() []Focusable {
+	var focusables []Focusable
+	for _, child := range panel.Children {
+		if childPanel, ok := child.(*Panel); ok {
+			focusables = append(focusables, childPanel.focusables()...)
+		} else if focusable, ok := child.(Focusable); ok {
+			focusables = append(focusables, focusable)
+		}
+	}
+	return focusables
+}
+
+// UI is a retained-mode widget tree, anchored to the screen, with keyboard focus navigation.
+type UI struct {
+	Root   *Panel
+	Anchor Anchor
+	Offset [2]float64 // Distance from the anchor, towards the middle of the screen
+	Point  [2]float64 // Screen position the UI is attached to, used by AnchorPoint
+	Modal  bool       // Dim everything under the UI
+	Theme  *Theme
+	focus  int
+}
+
+// NewUI creates a UI with the default theme, centered on the screen.
+func NewUI(root *Panel) *UI {
+	return &UI{Root: root, Anchor: AnchorCenter, Theme: DefaultTheme}
+}
+
+// Layout measures the widget tree and places it on the screen according to the anchor.
+func (ui *UI) Layout() {
+	width, height := ui.Root.Measure(ui.Theme)
+	x := (ScreenWidth - width) / 2
+	y := (ScreenHeight - height) / 2
+
+	switch ui.Anchor {
+	case AnchorTop, AnchorTopLeft, AnchorTopRight:
+		y = ui.Offset[1]
+	case AnchorBottom, AnchorBottomLeft, AnchorBottomRight:
+		y = ScreenHeight - height - ui.Offset[1]
+	}
+	switch ui.Anchor {
+	case AnchorLeft, AnchorTopLeft, AnchorBottomLeft:
+		x = ui.Offset[0]
+	case AnchorRight, AnchorTopRight, AnchorBottomRight:
+		x = ScreenWidth - width - ui.Offset[0]
+	}
+	if ui.Anchor == AnchorPoint {
+		x = ui.Point[0] - width/2 + ui.Offset[0]
+		y = ui.Point[1] - height - ui.Offset[1]
+	}
+
+	// Keep the whole UI on the screen
+	x = max(0, min(x, ScreenWidth-width))
+	y = max(0, min(y, ScreenHeight-height))
+
+	ui.Root.LayoutWithTheme(Rect{X: x, Y: y, Width: width, Height: height}, ui.Theme)
+}
+
+// Focused returns the widget with the keyboard focus, or nil if there are no focusable widgets.
+func (ui *UI) Focused() Focusable {
+	focusables := ui.Root.focusables()
+	if len(focusables) == 0 {
+		return nil
+	}
+	return focusables[ui.focus%len(focusables)]
+}
+
+// Focus gives the keyboard focus to the widget.
+func (ui *UI) Focus(widget Focusable) {
+	for i, focusable := range ui.Root.focusables() {
+		if focusable == widget {
+			ui.focus = i
+		}
+	}
+}
+
+// FocusFirst gives the keyboard focus to the first focusable widget.
+func (ui *UI) FocusFirst() {
+	ui.focus = 0
+}
+
+// moveFocus moves the focus by the given number of widgets, wrapping around at the ends.
+func (ui *UI) moveFocus(step int) {
+	count := len(ui.Root.focusables())
+	if count > 0 {
+		ui.focus = ((ui.focus+step)%count + count) % count
+	}
+}
+
+// Update lays out the widgets and handles the keys, the gamepad and the mouse.
+func (ui *UI) Update() {
+	ui.Layout()
+
+	keys := inpututil.AppendJustPressedKeys(nil)
+	keys = appendGamepadKeys(keys)
+	for _, key := range keys {
+		if focused := ui.Focused(); focused != nil && focused.HandleKey(key) {
+			if key == ebiten.KeyEnter || key == ebiten.KeySpace {
+				pressed()
+			}
+			continue
+		}
+		switch {
+		case key == ebiten.KeyTab && ebiten.IsKeyPressed(ebiten.KeyShift), key == ebiten.KeyArrowUp:
+			ui.moveFocus(-1)
+		case key == ebiten.KeyTab, key == ebiten.KeyArrowDown:
+			ui.moveFocus(1)
+		}
+	}
+
+	cursorX, cursorY := ebiten.CursorPosition()
+	for i, focusable := range ui.Root.focusables() {
+		if !focusable.Bounds().Contains(float64(cursorX), float64(cursorY)) {
+			continue
+		}
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			ui.focus = i
+			focusable.Click(float64(cursorX), float64(cursorY))
+			pressed()
+		}
+	}
+}
+
+// OnPress is called when a widget of any UI is pressed. Optional.
+var OnPress func()
+
+func pressed() {
+	if OnPress != nil {
+		OnPress()
+	}
+}
+
+// gamepadKeys maps the buttons of a standard gamepad to the keys they stand for in the UI.
+var gamepadKeys = map[ebiten.StandardGamepadButton]ebiten.Key{
+	ebiten.StandardGamepadButtonLeftTop:     ebiten.KeyArrowUp,
+	ebiten.StandardGamepadButtonLeftBottom:  ebiten.KeyArrowDown,
+	ebiten.StandardGamepadButtonLeftLeft:    ebiten.KeyArrowLeft,
+	ebiten.StandardGamepadButtonLeftRight:   ebiten.KeyArrowRight,
+	ebiten.StandardGamepadButtonRightBottom: ebiten.KeyEnter,
+}
+
+// appendGamepadKeys appends the keys matching the gamepad buttons pressed in this tick.
+func appendGamepadKeys(keys []ebiten.Key) []ebiten.Key {
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		for button, key := range gamepadKeys {
+			if inpututil.IsStandardGamepadButtonJustPressed(id, button) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+var modalOverlayColor = color.RGBA{0x00, 0x00, 0x00, 0x60}
+
+// Draw draws the widget tree where it was last laid out.
+func (ui *UI) Draw(screen *ebiten.Image) {
+	ui.Layout()
+	if ui.Modal {
+		vector.DrawFilledRect(screen, 0, 0, ScreenWidth, ScreenHeight, modalOverlayColor, false)
+	}
+	ui.Root.drawWithFocus(screen, ui.Theme, ui.Focused())
+}
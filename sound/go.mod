@@ -0,0 +1,21 @@
+module github.com/sound
+
+go 1.24.2
+
+require (
+	github.com/hajimehoshi/ebiten/v2 v2.8.8
+	github.com/logging v0.0.0-00010101000000-000000000000
+	github.com/utils v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/ebitengine/oto/v3 v3.3.3 // indirect
+	github.com/ebitengine/purego v0.8.0 // indirect
+	github.com/jfreymuth/oggvorbis v1.0.5 // indirect
+	github.com/jfreymuth/vorbis v1.0.2 // indirect
+	golang.org/x/sys v0.25.0 // indirect
+)
+
+replace github.com/logging => ../logging
+
+replace github.com/utils => ../utils
@@ -0,0 +1,83 @@
+package sound
+
+import (
+	"math"
+	"math/rand/v2"
+)
+
+// placeholders synthesize the sounds that have no file, so the game isn't silent without the audio assets.
+var placeholders = map[string]func() []byte{
+	// Slow A minor arpeggio
+	MenuMusic: func() []byte {
+		return melody([]float64{220.00, 261.63, 329.63, 261.63, 196.00, 246.94, 293.66, 246.94}, 0.5, 0.12)
+	},
+	// Low D minor walk through the burning city
+	StratholmeMusic: func() []byte {
+		return melody([]float64{146.83, 174.61, 220.00, 174.61, 155.56, 185.00, 220.00, 207.65}, 0.35, 0.12)
+	},
+	// Fast tritones for the bosses
+	BossMusic: func() []byte {
+		return melody([]float64{110.00, 155.56, 110.00, 164.81, 103.83, 146.83, 103.83, 155.56}, 0.18, 0.14)
+	},
+	DeathAndDecay: func() []byte { return tone(180, 50, 0.7, 0.5, 0.6) },
+	Purge:         func() []byte { return tone(440, 880, 0.25, 0.4, 0.1) },
+	NPCDeath:      func() []byte { return tone(320, 70, 0.35, 0.4, 0.3) },
+	LevelUp: func() []byte {
+		var samples []byte
+		for _, frequency := range []float64{523.25, 659.25, 783.99, 1046.50} {
+			samples = append(samples, tone(frequency, frequency, 0.12, 0.35, 0)...)
+		}
+		return samples
+	},
+	Click: func() []byte { return tone(1200, 900, 0.04, 0.25, 0) },
+}
+
+// placeholder returns the synthesized sound, or a short silence for a sound that has no placeholder either.
+func placeholder(name string) []byte {
+	if synthesize, ok := placeholders[name]; ok {
+		return synthesize()
+	}
+	logger.Warn("no sound", "sound", name)
+	return make([]byte, SampleRate/10*4)
+}
+
+// noise is separate from the game's random numbers, so the sounds don't change a seeded game.
+var noise = rand.New(rand.NewPCG(1, 2))
+
+// tone synthesizes a sine sweeping from one frequency to another, mixed with some noise,
+// with a short attack and a linear release. The samples are 16-bit stereo.
+func tone(startFrequency, endFrequency, seconds, volume, noiseAmount float64) []byte {
+	count := int(seconds * SampleRate)
+	samples := make([]byte, count*4)
+	attack := 0.005 * SampleRate
+
+	phase := 0.0
+	for i := range count {
+		progress := float64(i) / float64(count)
+		frequency := startFrequency + (endFrequency-startFrequency)*progress
+		phase += 2 * math.Pi * frequency / SampleRate
+
+		envelope := min(float64(i)/attack, 1) * (1 - progress)
+		value := (math.Sin(phase)*(1-noiseAmount) + (noise.Float64()*2-1)*noiseAmount) * envelope * volume
+		putSample(samples[i*4:], value)
+		putSample(samples[i*4+2:], value)
+	}
+	return samples
+}
+
+// melody synthesizes the notes one after the other over a bass an octave below, to be looped.
+func melody(frequencies []float64, noteSeconds, volume float64) []byte {
+	var samples []byte
+	for _, frequency := range frequencies {
+		note := tone(frequency, frequency, noteSeconds, volume, 0)
+		bass := tone(frequency/2, frequency/2, noteSeconds, volume, 0)
+		for i := 0; i+1 < len(note); i += 2 {
+			mixed := int16(uint16(note[i])|uint16(note[i+1])<<8) / 2
+			mixed += int16(uint16(bass[i])|uint16(bass[i+1])<<8) / 2
+			note[i] = byte(mixed)
+			note[i+1] = byte(uint16(mixed) >> 8)
+		}
+		samples = append(samples, note...)
+	}
+	return samples
+}
@@ -0,0 +1,217 @@
+package sound
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/hajimehoshi/ebiten/v2/audio/vorbis"
+	"github.com/hajimehoshi/ebiten/v2/audio/wav"
+
+	"github.com/logging"
+	"github.com/utils"
+)
+
+// SampleRate of the audio context, the files are resampled to it.
+const SampleRate = 44100
+
+// Seconds the music takes to fade from one track to the next.
+const crossfadeDuration = 1.5
+
+// Music tracks, looped while the game is in the matching state.
+const (
+	MenuMusic       = "menu"
+	StratholmeMusic = "stratholme"
+	BossMusic       = "boss"
+)
+
+// Sound effects.
+const (
+	DeathAndDecay = "death-and-decay"
+	Purge         = "purge"
+	NPCDeath      = "npc-death"
+	LevelUp       = "level-up"
+	Click         = "click"
+)
+
+var logger = logging.For(logging.Sound)
+
+// Manager plays the music and the sound effects. The sounds are loaded from assets/audio/<name>.ogg or .wav,
+// and the ones without a file are synthesized, see placeholders.
+type Manager struct {
+	Master  float64 // Volume of everything, from 0 to 1
+	Music   float64 // Volume of the music, from 0 to 1
+	Effects float64 // Volume of the sound effects, from 0 to 1
+	context *audio.Context
+	sounds  map[string][]byte // Decoded 16-bit stereo samples by sound name
+	track   string
+	music   *audio.Player
+	fading  *audio.Player // The previous track while it fades out
+	fade    float64       // Progress of the crossfade, from 0 to 1
+	playing []*audio.Player
+}
+
+// NewManager creates the audio context with the volumes. There can only be one manager.
+func NewManager(master, music, effects float64) *Manager {
+	return &Manager{
+		Master:  master,
+		Music:   music,
+		Effects: effects,
+		context: audio.NewContext(SampleRate),
+		sounds:  map[string][]byte{},
+		fade:    1,
+	}
+}
+
+// PlayMusic crossfades to the track, and loops it. Playing the current track does nothing.
+func (manager *Manager) PlayMusic(track string) {
+	if track == manager.track {
+		return
+	}
+	manager.track = track
+
+	samples := manager.samples(track)
+	player, err := manager.context.NewPlayer(audio.NewInfiniteLoop(bytes.NewReader(samples), int64(len(samples))))
+	if err != nil {
+		logger.Error("playing the music", "track", track, "err", err)
+		return
+	}
+	if manager.fading != nil {
+		manager.fading.Close()
+	}
+	manager.fading = manager.music
+	manager.music = player
+	manager.fade = 0
+	manager.updateMusicVolume()
+	player.Play()
+}
+
+// PlayEffect plays the sound effect once. The pan goes from -1 (left) to 1 (right), see Pan.
+func (manager *Manager) PlayEffect(name string, pan float64) {
+	volume := manager.Master * manager.Effects
+	if volume == 0 {
+		return
+	}
+	player := manager.context.NewPlayerFromBytes(panned(manager.samples(name), pan))
+	player.SetVolume(volume)
+	player.Play()
+	manager.playing = append(manager.playing, player)
+}
+
+// Pan returns the pan of a sound coming from the x position on a screen of the given width.
+func Pan(x, width float64) float64 {
+	return max(-1, min(x/width*2-1, 1))
+}
+
+// SetVolumes changes the volumes of the music, including the track that is playing, and of the next sound effects.
+func (manager *Manager) SetVolumes(master, music, effects float64) {
+	manager.Master = master
+	manager.Music = music
+	manager.Effects = effects
+	manager.updateMusicVolume()
+}
+
+// Update advances the crossfade and releases the sound effects that are over. It is called each game tick.
+func (manager *Manager) Update(delta float64) {
+	if manager.fade < 1 {
+		manager.fade = min(manager.fade+delta/crossfadeDuration, 1)
+		manager.updateMusicVolume()
+		if manager.fade == 1 && manager.fading != nil {
+			manager.fading.Close()
+			manager.fading = nil
+		}
+	}
+
+	playing := manager.playing[:0]
+	for _, player := range manager.playing {
+		if player.IsPlaying() {
+			playing = append(playing, player)
+		} else {
+			player.Close()
+		}
+	}
+	manager.playing = playing
+}
+
+func (manager *Manager) updateMusicVolume() {
+	volume := manager.Master * manager.Music
+	if manager.music != nil {
+		manager.music.SetVolume(volume * manager.fade)
+	}
+	if manager.fading != nil {
+		manager.fading.SetVolume(volume * (1 - manager.fade))
+	}
+}
+
+// samples returns the decoded sound, loading it on first use.
+func (manager *Manager) samples(name string) []byte {
+	if samples, ok := manager.sounds[name]; ok {
+		return samples
+	}
+
+	samples, err := load(name)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("loading the sound, using a placeholder", "sound", name, "err", err)
+		}
+		samples = placeholder(name)
+	}
+	manager.sounds[name] = samples
+	return samples
+}
+
+// load decodes assets/audio/<name>.ogg, or assets/audio/<name>.wav if there is no Ogg file.
+func load(name string) ([]byte, error) {
+	decoders := []struct {
+		extension string
+		decode    func(file io.Reader) (io.Reader, error)
+	}{
+		{".ogg", func(file io.Reader) (io.Reader, error) { return vorbis.DecodeWithSampleRate(SampleRate, file) }},
+		{".wav", func(file io.Reader) (io.Reader, error) { return wav.DecodeWithSampleRate(SampleRate, file) }},
+	}
+
+	for _, decoder := range decoders {
+		file, err := os.Open(filepath.Join(utils.AssetPath, "audio", name+decoder.extension))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		stream, err := decoder.decode(file)
+		if err != nil {
+			return nil, err
+		}
+		logger.Debug("sound loaded", "sound", name, "file", file.Name())
+		return io.ReadAll(stream)
+	}
+	return nil, os.ErrNotExist
+}
+
+// panned returns a copy of the 16-bit stereo samples with the equal-power pan applied.
+// A centered sound keeps its volume.
+func panned(samples []byte, pan float64) []byte {
+	angle := (pan + 1) * math.Pi / 4
+	left := min(math.Sqrt2*math.Cos(angle), 1)
+	right := min(math.Sqrt2*math.Sin(angle), 1)
+
+	result := make([]byte, len(samples))
+	for i := 0; i+3 < len(samples); i += 4 {
+		putSample(result[i:], float64(int16(uint16(samples[i])|uint16(samples[i+1])<<8))/math.MaxInt16*left)
+		putSample(result[i+2:], float64(int16(uint16(samples[i+2])|uint16(samples[i+3])<<8))/math.MaxInt16*right)
+	}
+	return result
+}
+
+// putSample writes a sample from -1 to 1 as a 16-bit little-endian integer.
+func putSample(buffer []byte, value float64) {
+	sample := int16(max(-1, min(value, 1)) * math.MaxInt16)
+	buffer[0] = byte(sample)
+	buffer[1] = byte(uint16(sample) >> 8)
+}
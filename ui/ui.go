@@ -1,14 +1,284 @@
 package ui
 
 import (
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/actor"
+	"github.com/events"
+	"github.com/gameplay"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/input"
+	"github.com/player"
+	"github.com/rendering"
+)
+
+const (
+	barWidth     = 200
+	barHeight    = 12
+	buttonSize   = 44
+	buttonMargin = 8
+	sidePanelX   = rendering.ScreenWidth - 160
+
+	announcementDuration = 2.5 // Seconds an announcement stays on the screen
 )
 
+var (
+	healthColor = color.RGBA{0xC0, 0x20, 0x20, 0xFF}
+	manaColor   = color.RGBA{0x20, 0x50, 0xD0, 0xFF}
+	xpColor     = color.RGBA{0x90, 0x30, 0xC0, 0xFF}
+	bossColor   = color.RGBA{0xB0, 0x10, 0x10, 0xFF}
+	overlayBg   = color.RGBA{0x00, 0x00, 0x00, 0xC0}
+	buttonBg    = color.RGBA{0x30, 0x30, 0x30, 0xFF}
+)
+
+// Hud draws the heads-up display over the game - the player's stats, the action bar,
+// the nameplates, the kill feed, the boss health bars, the announcements and the controls overlay.
 type Hud struct {
-	GameMenu        []*ebiten.Image // game menu images (start, pause, etc.)
-	PlayerControls  []*ebiten.Image // help overlay for player controls
-	PlayerAbilities []*ebiten.Image // buttons for player abilities (with bindings)
-	PlayerStats     []*ebiten.Image // player stats (health, mana, etc.)
-	PlayerNameplate *ebiten.Image   // player nameplate
-	NPCNameplate    *ebiten.Image   // NPC nameplate
+	Controls      []gameplay.ControlHint // help overlay for player controls
+	ShowControls  bool                   // the controls overlay is toggled on
+	Bindings      *input.Mapper          // inputs shown on the overlay and the action bar
+	announcements []announcement
+}
+
+// announcement is a line shown in the middle of the screen for a few seconds, e.g. a level-up.
+type announcement struct {
+	text string
+	left float64 // Seconds left on the screen
+}
+
+// NewHud creates a HUD with the controls of the current play mode.
+func NewHud(controls []gameplay.ControlHint, bindings *input.Mapper) *Hud {
+	return &Hud{Controls: controls, Bindings: bindings}
+}
+
+// Listen announces the player's level-ups, with the stats of the new level.
+func (hud *Hud) Listen(bus *events.Bus) {
+	events.Subscribe(bus, func(event gameplay.LevelUp) {
+		hud.Announce("Level " + strconv.Itoa(event.Level) + "!")
+		hud.Announce("Health " + strconv.Itoa(event.MaxHealth) + ", mana " + strconv.Itoa(event.MaxMana))
+	})
+}
+
+// Announce shows the text in the middle of the screen for a few seconds, under the older announcements.
+func (hud *Hud) Announce(text string) {
+	hud.announcements = append(hud.announcements, announcement{text: text, left: announcementDuration})
+}
+
+// Update ages the announcements by the tick duration in seconds and drops the expired ones.
+func (hud *Hud) Update(delta float64) {
+	shown := hud.announcements[:0]
+	for _, line := range hud.announcements {
+		line.left -= delta
+		if line.left > 0 {
+			shown = append(shown, line)
+		}
+	}
+	hud.announcements = shown
+}
+
+// ToggleControls shows or hides the controls overlay.
+func (hud *Hud) ToggleControls() {
+	hud.ShowControls = !hud.ShowControls
+}
+
+// DrawNameplates draws the nameplates of the player and of the living NPCs.
+// They are drawn before the panels of the HUD, so the panels stay on top of them.
+func (hud *Hud) DrawNameplates(screen *ebiten.Image, player *player.Player, npcActors []*actor.Actor) {
+	hud.DrawNameplate(screen, player.Actor)
+	for _, npc := range npcActors {
+		if npc.Present() {
+			hud.DrawNameplate(screen, npc)
+		}
+	}
+}
+
+// Draw draws the panels of the HUD - the player's stats, the target, the action bar, the kill feed, the bosses, the announcements and the controls.
+func (hud *Hud) Draw(screen *ebiten.Image, gameState *gameplay.GameState, player *player.Player, bosses []*gameplay.Boss) {
+	hud.DrawPlayerStats(screen, player)
+	hud.DrawTargetFrame(screen, player, bosses)
+	hud.DrawAbilityBar(screen, player)
+	hud.DrawKillFeed(screen, gameState)
+	hud.DrawBossHealthBars(screen, bosses)
+	hud.DrawAnnouncements(screen)
+
+	if hud.ShowControls {
+		hud.DrawControls(screen)
+	}
+}
+
+// DrawPlayerStats draws the player's level and unspent talent points with the health, mana and experience bars in the top-left corner.
+func (hud *Hud) DrawPlayerStats(screen *ebiten.Image, player *player.Player) {
+	stats := player.Actor.Name + " - Level " + strconv.Itoa(player.Level)
+	if player.TalentPoints > 0 {
+		stats += " - " + strconv.Itoa(player.TalentPoints) + " talent points"
+	}
+	rendering.DrawText(screen, stats, 10, 8)
+
+	rendering.DrawProgressBar(screen, 10, 24, barWidth, barHeight, ratio(player.Health, player.MaxHealth), healthColor)
+	rendering.DrawCenteredText(screen, strconv.Itoa(player.Health)+"/"+strconv.Itoa(player.MaxHealth), 10+barWidth/2, 24+barHeight/2)
+
+	rendering.DrawProgressBar(screen, 10, 40, barWidth, barHeight, ratio(player.Mana, player.MaxMana), manaColor)
+	rendering.DrawCenteredText(screen, strconv.Itoa(player.Mana)+"/"+strconv.Itoa(player.MaxMana), 10+barWidth/2, 40+barHeight/2)
+
+	rendering.DrawProgressBar(screen, 10, 56, barWidth, barHeight, ratio(player.XP, player.XPNeeded()), xpColor)
+	rendering.DrawCenteredText(screen, "XP "+strconv.Itoa(player.XP)+"/"+strconv.Itoa(player.XPNeeded()), 10+barWidth/2, 56+barHeight/2)
+}
+
+// DrawTargetFrame draws the player's target under the player's stats - its name, archetype and health.
+// Only the bosses take several hits, the other NPCs are shown at full health until they fall.
+func (hud *Hud) DrawTargetFrame(screen *ebiten.Image, player *player.Player, bosses []*gameplay.Boss) {
+	if !player.HasTarget() {
+		return
+	}
+	target := player.Target
+
+	health := 1.0
+	healthText := "100%"
+	for _, boss := range bosses {
+		if boss.Actor == target {
+			health = boss.Health / boss.MaxHealth
+			healthText = strconv.Itoa(int(math.Ceil(boss.Health))) + "/" + strconv.Itoa(int(boss.MaxHealth))
+		}
+	}
+
+	rendering.DrawColoredRect(screen, 6, 76, barWidth+8, 52, overlayBg)
+	rendering.DrawText(screen, "Target: "+target.Name, 10, 80)
+	if target.Archetype != "" {
+		rendering.DrawText(screen, target.Archetype, 10, 94)
+	}
+	rendering.DrawProgressBar(screen, 10, 110, barWidth, barHeight, health, healthColor)
+	rendering.DrawCenteredText(screen, healthText, 10+barWidth/2, 110+barHeight/2)
+}
+
+// DrawAbilityBar draws a button for each ability on the player's action bar at the bottom center of the screen.
+// Each button shows the ability's icon, its key and a sweep over the part of the cooldown that is left.
+// Abilities the player can't pay for are dimmed.
+func (hud *Hud) DrawAbilityBar(screen *ebiten.Image, player *player.Player) {
+	slots := player.AbilitySlots
+	barWidth := float32(len(slots))*(buttonSize+buttonMargin) - buttonMargin
+	x := rendering.ScreenWidth/2 - barWidth/2
+	y := float32(rendering.ScreenHeight - buttonSize - buttonMargin)
+
+	for _, slot := range slots {
+		rendering.DrawColoredRect(screen, x, y, buttonSize, buttonSize, buttonBg)
+		if slot.Icon != nil {
+			iconBounds := slot.Icon.Bounds()
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(buttonSize/float64(iconBounds.Dx()), buttonSize/float64(iconBounds.Dy()))
+			op.GeoM.Translate(float64(x), float64(y))
+			if player.Mana < slot.ManaCost {
+				op.ColorScale.Scale(0.4, 0.4, 0.4, 1)
+			}
+			screen.DrawImage(slot.Icon, op)
+		}
+
+		if slot.Cooldown > 0 {
+			remaining := slot.CooldownRemaining()
+			rendering.DrawCooldownSweep(screen, x, y, buttonSize, remaining/slot.EffectiveCooldown())
+			if remaining > 0 {
+				rendering.DrawCenteredText(screen, strconv.Itoa(int(math.Ceil(remaining))), float64(x+buttonSize/2), float64(y+buttonSize/2))
+			}
+		}
+		rendering.DrawText(screen, hud.Bindings.KeyLabel(input.Action(slot.Action)), float64(x+3), float64(y+3))
+
+		x += buttonSize + buttonMargin
+	}
+}
+
+// DrawNameplate draws the actor's name centered above it.
+func (hud *Hud) DrawNameplate(screen *ebiten.Image, gameActor *actor.Actor) {
+	rect := gameActor.GetBoundingRect()
+	rendering.DrawCenteredText(screen, gameActor.Name, rect.PositionX+rect.Width/2, rect.PositionY-8)
+}
+
+// DrawKillFeed draws the purged and spared counts with the wave progress in the top-right corner.
+func (hud *Hud) DrawKillFeed(screen *ebiten.Image, gameState *gameplay.GameState) {
+	rendering.DrawText(screen, "Purged: "+strconv.Itoa(gameState.PurgedCount), sidePanelX, 0)
+	rendering.DrawText(screen, "Spared: "+strconv.Itoa(gameState.SparedCount), sidePanelX, 20)
+	rendering.DrawText(screen, waveStr(gameState), sidePanelX, 40)
+	rendering.DrawText(screen, countdownStr(gameState), sidePanelX, 60)
+}
+
+func waveStr(gameState *gameplay.GameState) string {
+	if gameState.WaveCount == 0 {
+		return "Wave: " + strconv.Itoa(gameState.Wave)
+	}
+	return "Wave: " + strconv.Itoa(gameState.Wave) + "/" + strconv.Itoa(gameState.WaveCount)
+}
+
+// countdownStr returns the time left for a timed win condition,
+// or the time until the next wave if the mode is not timed.
+func countdownStr(gameState *gameplay.GameState) string {
+	if gameState.TimeLeft > 0 {
+		return "Time left: " + strconv.Itoa(int(math.Ceil(gameState.TimeLeft))) + "s"
+	}
+	if gameState.NextWaveIn > 0 {
+		return "Next wave: " + strconv.Itoa(int(math.Ceil(gameState.NextWaveIn))) + "s"
+	}
+	return ""
+}
+
+// DrawBossHealthBars draws a health bar with the name and the phase of each boss still standing,
+// stacked at the top center of the screen.
+func (hud *Hud) DrawBossHealthBars(screen *ebiten.Image, bosses []*gameplay.Boss) {
+	bossBarWidth := float32(300)
+	y := float32(10)
+	for _, boss := range bosses {
+		if boss.Defeated() {
+			continue
+		}
+		label := boss.Actor.Name + " - " + boss.CurrentPhase().Name
+		rendering.DrawCenteredText(screen, label, rendering.ScreenWidth/2, float64(y))
+		rendering.DrawProgressBar(screen, rendering.ScreenWidth/2-bossBarWidth/2, y+10, bossBarWidth, 12, boss.Health/boss.MaxHealth, bossColor)
+		y += 40
+	}
+}
+
+// DrawAnnouncements draws the announcements stacked in the upper middle of the screen, under the boss health bars.
+func (hud *Hud) DrawAnnouncements(screen *ebiten.Image) {
+	for i, line := range hud.announcements {
+		rendering.DrawCenteredText(screen, line.text, rendering.ScreenWidth/2, rendering.ScreenHeight/4+float64(i*20))
+	}
+}
+
+// DrawControls draws the controls overlay in the middle of the screen.
+func (hud *Hud) DrawControls(screen *ebiten.Image) {
+	width := float32(420)
+	height := float32(40 + 20*len(hud.Controls))
+	x := rendering.ScreenWidth/2 - width/2
+	y := rendering.ScreenHeight/2 - height/2
+
+	rendering.DrawColoredRect(screen, x, y, width, height, overlayBg)
+	rendering.DrawCenteredText(screen, "Controls", rendering.ScreenWidth/2, float64(y+15))
+	for i, hint := range hud.Controls {
+		lineY := float64(y) + 40 + float64(i)*20
+		rendering.DrawText(screen, hud.hintLabel(hint), float64(x+20), lineY)
+		rendering.DrawText(screen, hint.Description, float64(x+260), lineY)
+	}
+}
+
+// hintLabel returns the inputs bound to the hint's actions, like "Up/Left/Down/Right".
+// Hints with a single action also show its gamepad button, and hints without actions show their fixed input.
+func (hud *Hud) hintLabel(hint gameplay.ControlHint) string {
+	if len(hint.Actions) == 0 {
+		return hint.Input
+	}
+	if len(hint.Actions) == 1 {
+		return hud.Bindings.Label(hint.Actions[0])
+	}
+	labels := make([]string, 0, len(hint.Actions))
+	for _, action := range hint.Actions {
+		labels = append(labels, hud.Bindings.KeyLabel(action))
+	}
+	return strings.Join(labels, "/")
+}
+
+func ratio(value, maxValue int) float64 {
+	if maxValue <= 0 {
+		return 0
+	}
+	return float64(value) / float64(maxValue)
 }
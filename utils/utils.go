@@ -4,10 +4,33 @@ import (
 	"log"
 	"math/rand/v2"
 	"os"
+	"path/filepath"
+	"strings"
 )
 
+// AssetPath is the directory the "./assets/" files are loaded from.
+var AssetPath = "./assets"
+
+// random is the source of the random numbers, seeded by Seed.
+var random = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+// Seed makes the random numbers repeat from game to game. A seed of 0 keeps them random.
+func Seed(seed uint64) {
+	if seed != 0 {
+		random = rand.New(rand.NewPCG(seed, seed))
+	}
+}
+
+// AssetFile returns the path of the file, with the "./assets/" files under AssetPath.
+func AssetFile(path string) string {
+	if asset, ok := strings.CutPrefix(path, "./assets/"); ok {
+		return filepath.Join(AssetPath, asset)
+	}
+	return path
+}
+
 func LoadFile(path string) *os.File {
-	file, err := os.Open(path) // Path to your image
+	file, err := os.Open(AssetFile(path)) // Path to your image
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -17,5 +40,5 @@ func LoadFile(path string) *os.File {
 
 // Utils ---- move to module?
 func GetRandomNumInRange(minLimit float64, maxLimit float64) float64 {
-	return minLimit + rand.Float64()*(maxLimit-minLimit)
+	return minLimit + random.Float64()*(maxLimit-minLimit)
 }